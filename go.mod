@@ -5,6 +5,8 @@ go 1.21.0
 require (
 	github.com/hashicorp/go-hclog v1.6.2
 	github.com/turbot/steampipe-plugin-sdk/v5 v5.8.0
+	golang.org/x/time v0.5.0
+	google.golang.org/protobuf v1.32.0
 )
 
 require github.com/golang/protobuf v1.5.3 // indirect
@@ -20,7 +22,6 @@ require (
 	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.47.0 // indirect
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.47.0 // indirect
 	golang.org/x/crypto v0.18.0 // indirect
-	golang.org/x/time v0.5.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20240205150955-31a09d347014 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240205150955-31a09d347014 // indirect
 )
@@ -107,6 +108,5 @@ require (
 	google.golang.org/appengine v1.6.8 // indirect
 	google.golang.org/genproto v0.0.0-20240205150955-31a09d347014 // indirect
 	google.golang.org/grpc v1.61.0 // indirect
-	google.golang.org/protobuf v1.32.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
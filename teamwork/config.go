@@ -1,33 +1,360 @@
 package teamwork
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/schema"
 )
 
 type teamworkConfig struct {
-	APIKey *string `cty:"api_key"`
-	Domain *string `cty:"domain"`
+	AccessToken       *string `cty:"access_token"`
+	APIKey            *string `cty:"api_key"`
+	APIVersion        *string `cty:"api_version"`
+	AuthMethod        *string `cty:"auth_method"`
+	BaseURL           *string `cty:"base_url"`
+	ClientID          *string `cty:"client_id"`
+	ClientSecret      *string `cty:"client_secret"`
+	CredentialsFile   *string `cty:"credentials_file"`
+	Domain            *string `cty:"domain"`
+	MaxConcurrency    *int    `cty:"max_concurrency"`
+	MaxRetries        *int    `cty:"max_retries"`
+	MinRetryDelay     *int    `cty:"min_retry_delay"`
+	RefreshToken      *string `cty:"refresh_token"`
+	RequestTimeout    *int    `cty:"request_timeout"`
+	RequestsPerMinute *int    `cty:"requests_per_minute"`
+	TokenURL          *string `cty:"token_url"`
 }
 
 var ConfigSchema = map[string]*schema.Attribute{
+	"access_token": {
+		Type: schema.TypeString,
+	},
 	"api_key": {
 		Type: schema.TypeString,
 	},
+	"api_version": {
+		Type: schema.TypeString,
+	},
+	"auth_method": {
+		Type: schema.TypeString,
+	},
+	"base_url": {
+		Type: schema.TypeString,
+	},
+	"client_id": {
+		Type: schema.TypeString,
+	},
+	"client_secret": {
+		Type: schema.TypeString,
+	},
+	"credentials_file": {
+		Type: schema.TypeString,
+	},
 	"domain": {
 		Type: schema.TypeString,
 	},
+	"max_concurrency": {
+		Type: schema.TypeInt,
+	},
+	"max_retries": {
+		Type: schema.TypeInt,
+	},
+	"min_retry_delay": {
+		Type: schema.TypeInt,
+	},
+	"refresh_token": {
+		Type: schema.TypeString,
+	},
+	"request_timeout": {
+		Type: schema.TypeInt,
+	},
+	"requests_per_minute": {
+		Type: schema.TypeInt,
+	},
+	"token_url": {
+		Type: schema.TypeString,
+	},
 }
 
 func ConfigInstance() interface{} {
 	return &teamworkConfig{}
 }
 
+// Environment variables consulted for options that are not set in the connection
+// config or credentials file.
+const (
+	envAPIKey  = "TEAMWORK_API_KEY"
+	envBaseURL = "TEAMWORK_BASE_URL"
+	envDomain  = "TEAMWORK_DOMAIN"
+)
+
+// teamworkCredentials is the JSON document read from a connection's credentials_file.
+type teamworkCredentials struct {
+	APIKey  string `json:"api_key"`
+	BaseURL string `json:"base_url"`
+	Domain  string `json:"domain"`
+}
+
 // GetConfig :: retrieve and cast connection config from query data
+//
+// The API key and site are resolved in order of precedence from:
+//  1. api_key, base_url and domain in the connection config
+//  2. the same keys in the JSON file named by credentials_file
+//  3. the TEAMWORK_API_KEY, TEAMWORK_BASE_URL and TEAMWORK_DOMAIN environment variables
+//
+// base_url and domain are resolved together, so a domain in the connection config is
+// never overridden by a base_url from a lower precedence source.
 func GetConfig(connection *plugin.Connection) teamworkConfig {
-	if connection == nil || connection.Config == nil {
-		return teamworkConfig{}
+	var config teamworkConfig
+	if connection != nil && connection.Config != nil {
+		config, _ = connection.Config.(teamworkConfig)
+	}
+
+	var creds teamworkCredentials
+	if config.CredentialsFile != nil && *config.CredentialsFile != "" {
+		// An unreadable file is reported by validate
+		creds, _ = readCredentialsFile(*config.CredentialsFile)
 	}
-	config, _ := connection.Config.(teamworkConfig)
+	env := teamworkCredentials{
+		APIKey:  os.Getenv(envAPIKey),
+		BaseURL: os.Getenv(envBaseURL),
+		Domain:  os.Getenv(envDomain),
+	}
+
+	config.APIKey = firstSet(config.APIKey, creds.APIKey, env.APIKey)
+
+	if firstSet(config.BaseURL) == nil && firstSet(config.Domain) == nil {
+		for _, source := range []teamworkCredentials{creds, env} {
+			if source.BaseURL != "" || source.Domain != "" {
+				config.BaseURL = firstSet(nil, source.BaseURL)
+				config.Domain = firstSet(nil, source.Domain)
+				break
+			}
+		}
+	}
+
 	return config
 }
+
+// firstSet returns value if it is set, otherwise the first non-empty fallback.
+func firstSet(value *string, fallbacks ...string) *string {
+	if value != nil && *value != "" {
+		return value
+	}
+	for _, fallback := range fallbacks {
+		if fallback != "" {
+			return &fallback
+		}
+	}
+	return nil
+}
+
+// readCredentialsFile reads a JSON credentials file, expanding a leading ~ to the
+// user's home directory.
+func readCredentialsFile(path string) (teamworkCredentials, error) {
+	var creds teamworkCredentials
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return creds, err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return creds, err
+	}
+	if err := json.Unmarshal(contents, &creds); err != nil {
+		return creds, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return creds, nil
+}
+
+// validate reports every connection option that is missing or malformed.
+func (c teamworkConfig) validate() error {
+	var problems []string
+
+	if c.CredentialsFile != nil && *c.CredentialsFile != "" {
+		if _, err := readCredentialsFile(*c.CredentialsFile); err != nil {
+			problems = append(problems, fmt.Sprintf("credentials_file could not be read: %s", err))
+		}
+	}
+
+	hasAPIKey := c.APIKey != nil && strings.TrimSpace(*c.APIKey) != ""
+	hasAccessToken := c.AccessToken != nil && *c.AccessToken != ""
+	switch method := authMethod(c); method {
+	case authMethodBearer, authMethodBasic:
+		if !hasAPIKey {
+			if c.AuthMethod == nil || *c.AuthMethod == "" {
+				problems = append(problems, fmt.Sprintf(
+					"one of api_key or access_token is required (or set %s)", envAPIKey))
+			} else {
+				problems = append(problems, fmt.Sprintf(
+					"api_key is required for auth_method %q (or set %s)", method, envAPIKey))
+			}
+		}
+	case authMethodOAuth2:
+		if !hasAccessToken {
+			problems = append(problems, "access_token is required for auth_method \"oauth2\"")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("auth_method %q must be bearer, basic or oauth2", method))
+	}
+	if c.RefreshToken != nil && *c.RefreshToken != "" &&
+		(c.ClientID == nil || *c.ClientID == "" || c.ClientSecret == nil || *c.ClientSecret == "") {
+		problems = append(problems, "client_id and client_secret are required to use refresh_token")
+	}
+	if c.TokenURL != nil && *c.TokenURL != "" {
+		if u, err := url.Parse(*c.TokenURL); err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("token_url %q must be a full URL", *c.TokenURL))
+		}
+	}
+
+	hasBaseURL := c.BaseURL != nil && *c.BaseURL != ""
+	hasDomain := c.Domain != nil && *c.Domain != ""
+	switch {
+	case hasBaseURL:
+		if u, err := url.Parse(*c.BaseURL); err != nil ||
+			(u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf(
+				"base_url %q must be a full URL such as https://ourco.teamwork.com", *c.BaseURL))
+		}
+	case hasDomain:
+		if strings.Contains(*c.Domain, "://") || strings.ContainsAny(*c.Domain, "/.") {
+			problems = append(problems, fmt.Sprintf(
+				"domain %q must be the bare site name (use base_url for full URLs)", *c.Domain))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf(
+			"one of base_url or domain is required (or set %s or %s)", envBaseURL, envDomain))
+	}
+
+	if c.APIVersion != nil && *c.APIVersion != "" && *c.APIVersion != "v1" && *c.APIVersion != "v3" {
+		problems = append(problems, fmt.Sprintf("api_version %q must be v1 or v3", *c.APIVersion))
+	}
+
+	if c.MaxConcurrency != nil && *c.MaxConcurrency < 1 {
+		problems = append(problems, fmt.Sprintf("max_concurrency %d must be at least 1", *c.MaxConcurrency))
+	}
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		problems = append(problems, fmt.Sprintf("max_retries %d must not be negative", *c.MaxRetries))
+	}
+	if c.MinRetryDelay != nil && *c.MinRetryDelay < 0 {
+		problems = append(problems, fmt.Sprintf("min_retry_delay %d must not be negative", *c.MinRetryDelay))
+	}
+	if c.RequestTimeout != nil && *c.RequestTimeout <= 0 {
+		problems = append(problems, fmt.Sprintf("request_timeout %d must be positive", *c.RequestTimeout))
+	}
+	if c.RequestsPerMinute != nil && *c.RequestsPerMinute <= 0 {
+		problems = append(problems, fmt.Sprintf("requests_per_minute %d must be positive", *c.RequestsPerMinute))
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validConfig retrieves the connection config for a query and validates it, so that
+// a missing or malformed option is reported as an error by every table.
+func validConfig(d *plugin.QueryData) (teamworkConfig, error) {
+	config := GetConfig(d.Connection)
+	if err := config.validate(); err != nil {
+		name := ""
+		if d.Connection != nil {
+			name = d.Connection.Name
+		}
+		return teamworkConfig{}, fmt.Errorf(
+			"invalid config for connection %q, please check your teamwork.spc file: %w", name, err)
+	}
+	return config, nil
+}
+
+// teamworkURL builds the URL of a Teamwork API endpoint for the given connection config.
+// A configured base_url (e.g. https://ourco.teamwork.com or https://ourco.eu.teamwork.com)
+// takes precedence over the legacy domain option.
+func teamworkURL(config teamworkConfig, path string) string {
+	if config.BaseURL != nil && *config.BaseURL != "" {
+		return strings.TrimRight(*config.BaseURL, "/") + path
+	}
+	return fmt.Sprintf("https://teamwork.%s.com%s", *config.Domain, path)
+}
+
+// apiVersion returns the Teamwork API version ("v1" or "v3") used by tables that
+// support both, defaulting to v1.
+func apiVersion(config teamworkConfig) string {
+	if config.APIVersion == nil || *config.APIVersion == "" {
+		return "v1"
+	}
+	return *config.APIVersion
+}
+
+// Defaults for the max_concurrency, max_retries, min_retry_delay and request_timeout options.
+const (
+	defaultMaxConcurrency = 4
+	defaultMaxRetries     = 3
+	defaultMinRetryDelay  = 500 * time.Millisecond
+	defaultRequestTimeout = time.Minute
+)
+
+// maxConcurrency returns how many pages of a list are fetched at once.
+func maxConcurrency(config teamworkConfig) int {
+	if config.MaxConcurrency == nil {
+		return defaultMaxConcurrency
+	}
+	return *config.MaxConcurrency
+}
+
+// maxRetries returns how many times a throttled or failed request is retried.
+func maxRetries(config teamworkConfig) int {
+	if config.MaxRetries == nil {
+		return defaultMaxRetries
+	}
+	return *config.MaxRetries
+}
+
+// minRetryDelay returns the delay before the first retry, which doubles with each
+// further attempt. min_retry_delay is given in milliseconds.
+func minRetryDelay(config teamworkConfig) time.Duration {
+	if config.MinRetryDelay == nil {
+		return defaultMinRetryDelay
+	}
+	return time.Duration(*config.MinRetryDelay) * time.Millisecond
+}
+
+// requestTimeout returns how long a single request may take, including reading the
+// response body. request_timeout is given in seconds.
+func requestTimeout(config teamworkConfig) time.Duration {
+	if config.RequestTimeout == nil {
+		return defaultRequestTimeout
+	}
+	return time.Duration(*config.RequestTimeout) * time.Second
+}
+
+// Authentication methods accepted by the auth_method option.
+const (
+	authMethodBasic  = "basic"
+	authMethodBearer = "bearer"
+	authMethodOAuth2 = "oauth2"
+)
+
+// authMethod returns how requests are authenticated: with the API key as a bearer
+// token or as the HTTP Basic auth username, or with an OAuth2 access token. It defaults
+// to oauth2 when an access token is configured and bearer otherwise.
+func authMethod(config teamworkConfig) string {
+	if config.AuthMethod != nil && *config.AuthMethod != "" {
+		return *config.AuthMethod
+	}
+	if config.AccessToken != nil && *config.AccessToken != "" {
+		return authMethodOAuth2
+	}
+	return authMethodBearer
+}
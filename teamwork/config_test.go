@@ -0,0 +1,198 @@
+package teamwork
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func TestTeamworkURL(t *testing.T) {
+	domain := "example"
+	baseURL := "https://ourco.eu.teamwork.com/"
+	empty := ""
+
+	for _, test := range []struct {
+		Name   string
+		config teamworkConfig
+		want   string
+	}{
+		{"domain", teamworkConfig{Domain: &domain}, "https://teamwork.example.com/projects.json"},
+		{"base_url", teamworkConfig{BaseURL: &baseURL}, "https://ourco.eu.teamwork.com/projects.json"},
+		{
+			"base_url over domain",
+			teamworkConfig{BaseURL: &baseURL, Domain: &domain},
+			"https://ourco.eu.teamwork.com/projects.json",
+		},
+		{"empty base_url", teamworkConfig{BaseURL: &empty, Domain: &domain}, "https://teamwork.example.com/projects.json"},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := teamworkURL(test.config, "/projects.json"); got != test.want {
+				t.Errorf("unexpected url: got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTeamworkConfigValidate(t *testing.T) {
+	apiKey := "apiKey"
+	domain := "example"
+	badDomain := "https://teamwork.example.com"
+	baseURL := "https://ourco.teamwork.com"
+	badBaseURL := "ourco.teamwork.com"
+	v3 := "v3"
+	v2 := "v2"
+	basic := "basic"
+	digest := "digest"
+	negative := -1
+	zero := 0
+
+	for _, test := range []struct {
+		Name    string
+		config  teamworkConfig
+		wantErr string
+	}{
+		{"domain", teamworkConfig{APIKey: &apiKey, Domain: &domain}, ""},
+		{"base_url", teamworkConfig{APIKey: &apiKey, BaseURL: &baseURL, APIVersion: &v3}, ""},
+		{
+			"missing everything",
+			teamworkConfig{},
+			"one of api_key or access_token is required (or set TEAMWORK_API_KEY); " +
+				"one of base_url or domain is required (or set TEAMWORK_BASE_URL or TEAMWORK_DOMAIN)",
+		},
+		{
+			"domain with scheme",
+			teamworkConfig{APIKey: &apiKey, Domain: &badDomain},
+			`domain "https://teamwork.example.com" must be the bare site name (use base_url for full URLs)`,
+		},
+		{
+			"base_url without scheme",
+			teamworkConfig{APIKey: &apiKey, BaseURL: &badBaseURL},
+			`base_url "ourco.teamwork.com" must be a full URL such as https://ourco.teamwork.com`,
+		},
+		{
+			"basic without api_key",
+			teamworkConfig{Domain: &domain, AuthMethod: &basic},
+			`api_key is required for auth_method "basic" (or set TEAMWORK_API_KEY)`,
+		},
+		{
+			"unknown auth_method",
+			teamworkConfig{APIKey: &apiKey, Domain: &domain, AuthMethod: &digest},
+			`auth_method "digest" must be bearer, basic or oauth2`,
+		},
+		{
+			"unknown api_version",
+			teamworkConfig{APIKey: &apiKey, Domain: &domain, APIVersion: &v2},
+			`api_version "v2" must be v1 or v3`,
+		},
+		{
+			"negative retry options",
+			teamworkConfig{APIKey: &apiKey, Domain: &domain, MaxRetries: &negative, MinRetryDelay: &negative},
+			"max_retries -1 must not be negative; min_retry_delay -1 must not be negative",
+		},
+		{
+			"zero requests_per_minute",
+			teamworkConfig{APIKey: &apiKey, Domain: &domain, RequestsPerMinute: &zero},
+			"requests_per_minute 0 must be positive",
+		},
+		{
+			"zero max_concurrency",
+			teamworkConfig{APIKey: &apiKey, Domain: &domain, MaxConcurrency: &zero},
+			"max_concurrency 0 must be at least 1",
+		},
+		{
+			"zero request_timeout",
+			teamworkConfig{APIKey: &apiKey, Domain: &domain, RequestTimeout: &zero},
+			"request_timeout 0 must be positive",
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.config.validate()
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("unexpected error: got %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetConfigPrecedence(t *testing.T) {
+	credentialsFile := filepath.Join(t.TempDir(), "teamwork.json")
+	err := os.WriteFile(
+		credentialsFile,
+		[]byte(`{"api_key": "fileKey", "base_url": "https://file.teamwork.com"}`),
+		0o600,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv(envAPIKey, "envKey")
+	t.Setenv(envBaseURL, "https://env.teamwork.com")
+	t.Setenv(envDomain, "")
+
+	connKey := "connKey"
+	connDomain := "conn"
+
+	for _, test := range []struct {
+		Name        string
+		config      teamworkConfig
+		wantAPIKey  string
+		wantBaseURL string
+		wantDomain  string
+	}{
+		{"environment", teamworkConfig{}, "envKey", "https://env.teamwork.com", ""},
+		{
+			"credentials file over environment",
+			teamworkConfig{CredentialsFile: &credentialsFile},
+			"fileKey", "https://file.teamwork.com", "",
+		},
+		{
+			"connection config over credentials file",
+			teamworkConfig{APIKey: &connKey, Domain: &connDomain, CredentialsFile: &credentialsFile},
+			"connKey", "", "conn",
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			config := GetConfig(&plugin.Connection{Name: "teamwork", Config: test.config})
+
+			if got := derefString(config.APIKey); got != test.wantAPIKey {
+				t.Errorf("unexpected api_key: got %v, want %v", got, test.wantAPIKey)
+			}
+			if got := derefString(config.BaseURL); got != test.wantBaseURL {
+				t.Errorf("unexpected base_url: got %v, want %v", got, test.wantBaseURL)
+			}
+			if got := derefString(config.Domain); got != test.wantDomain {
+				t.Errorf("unexpected domain: got %v, want %v", got, test.wantDomain)
+			}
+		})
+	}
+}
+
+func TestGetConfigMissingCredentialsFile(t *testing.T) {
+	apiKey := "apiKey"
+	domain := "example"
+	credentialsFile := filepath.Join(t.TempDir(), "missing.json")
+
+	config := GetConfig(&plugin.Connection{
+		Name:   "teamwork",
+		Config: teamworkConfig{APIKey: &apiKey, Domain: &domain, CredentialsFile: &credentialsFile},
+	})
+	if err := config.validate(); err == nil || !strings.HasPrefix(err.Error(), "credentials_file could not be read") {
+		t.Errorf("unexpected error: got %v, want credentials_file error", err)
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
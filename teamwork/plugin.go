@@ -15,8 +15,28 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			NewInstance: ConfigInstance,
 			Schema:      ConfigSchema,
 		},
+		DefaultIgnoreConfig: &plugin.IgnoreConfig{
+			ShouldIgnoreErrorFunc: shouldIgnoreError,
+		},
+		// fetchPage retries each request up to max_retries times, so a failed hydrate
+		// call is retried only once more
+		DefaultRetryConfig: &plugin.RetryConfig{
+			ShouldRetryErrorFunc: shouldRetryError,
+			MaxAttempts:          1,
+		},
 		TableMap: map[string]*plugin.Table{
-			"teamwork_project": tableTeamworkProject(ctx),
+			"teamwork_comment":      tableTeamworkComment(ctx),
+			"teamwork_company":      tableTeamworkCompany(ctx),
+			"teamwork_file":         tableTeamworkFile(ctx),
+			"teamwork_file_version": tableTeamworkFileVersion(ctx),
+			"teamwork_milestone":    tableTeamworkMilestone(ctx),
+			"teamwork_person":       tableTeamworkPerson(ctx),
+			"teamwork_project":      tableTeamworkProject(ctx),
+			"teamwork_task":         tableTeamworkTask(ctx),
+			"teamwork_tasklist":     tableTeamworkTasklist(ctx),
+			"teamwork_team":         tableTeamworkTeam(ctx),
+			"teamwork_team_member":  tableTeamworkTeamMember(ctx),
+			"teamwork_time_entry":   tableTeamworkTimeEntry(ctx),
 		},
 	}
 	return p
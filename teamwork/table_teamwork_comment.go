@@ -0,0 +1,221 @@
+package teamwork
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// commentResources maps the object types that can be commented on to the
+// resource path used by their comment endpoints.
+var commentResources = map[string]string{
+	"task":      "tasks",
+	"milestone": "milestones",
+	"notebook":  "notebooks",
+	"file":      "fileversions",
+	"link":      "links",
+}
+
+// commentObjectTypes maps the commentable types returned by the API to the
+// object types used by the object_type column.
+var commentObjectTypes = map[string]string{
+	"todo_items":   "task",
+	"tasks":        "task",
+	"milestones":   "milestone",
+	"notebooks":    "notebook",
+	"files":        "file",
+	"fileversions": "file",
+	"links":        "link",
+}
+
+func tableTeamworkComment(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_comment",
+		Description: "Comments on tasks, milestones, notebooks, files and links from Teamwork.com",
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkComments,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "object_type",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "object_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the comment.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "body",
+				Type:        proto.ColumnType_STRING,
+				Description: "The plain text body of the comment.",
+				Transform:   transform.FromField("Body").NullIfZero(),
+			},
+			{
+				Name:        "html_body",
+				Type:        proto.ColumnType_STRING,
+				Description: "The HTML body of the comment.",
+				Transform:   transform.FromField("HTMLBody").NullIfZero(),
+			},
+			{
+				Name:        "author_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the person who posted the comment.",
+				Transform:   transform.FromField("AuthorID").NullIfZero(),
+			},
+			{
+				Name:        "author_first_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The first name of the person who posted the comment.",
+				Transform:   transform.FromField("AuthorFirstName").NullIfZero(),
+			},
+			{
+				Name:        "author_last_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The last name of the person who posted the comment.",
+				Transform:   transform.FromField("AuthorLastName").NullIfZero(),
+			},
+			{
+				Name:        "posted_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the comment was posted.",
+				Transform:   transform.FromField("Datetime").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "last_changed_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the comment was last edited.",
+				Transform:   transform.FromField("LastChangedOn").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "object_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of object the comment was posted on: task, milestone, notebook, file or link.",
+				Transform:   transform.From(commentObjectType),
+			},
+			{
+				Name:        "object_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the object the comment was posted on.",
+				Transform:   transform.FromField("CommentableID").NullIfZero(),
+			},
+			{
+				Name:        "project_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the project the comment belongs to.",
+				Transform:   transform.FromField("ProjectID").NullIfZero(),
+			},
+			{
+				Name:        "project_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the project the comment belongs to.",
+				Transform:   transform.FromField("ProjectName").NullIfZero(),
+			},
+			{
+				Name:        "private",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the comment is private.",
+				Transform:   transform.FromField("Private"),
+			},
+			{
+				Name:        "attachments_count",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of files attached to the comment.",
+				Transform:   transform.FromField("AttachmentsCount"),
+			},
+		},
+	}
+}
+
+func listTeamworkComments(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a list of comments, either for a
+	// single object or the most recent comments across the site
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkComments()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	path := "/comments.json"
+	resource, ok := commentResources[d.EqualsQualString("object_type")]
+	if objectID := d.EqualsQualString("object_id"); ok && objectID != "" {
+		path = fmt.Sprintf("/%s/%s/comments.json", resource, objectID)
+	}
+	url := client.url(path)
+	if params := limitPageSize(d, nil); len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkComments(): url: %s", url))
+
+	err = StreamTeamworkItems(ctx, client, url, plugin.Logger(ctx), func(page *CommentsResponse) bool {
+		for _, t := range page.Comments {
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkComments()")
+	return nil, nil
+}
+
+// commentObjectType normalises the commentable type returned by the API so
+// that it matches the values accepted by the object_type key column.
+func commentObjectType(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	comment, ok := d.HydrateItem.(Comment)
+	if !ok || comment.CommentableType == "" {
+		return nil, nil
+	}
+	if objectType, ok := commentObjectTypes[comment.CommentableType]; ok {
+		return objectType, nil
+	}
+	return comment.CommentableType, nil
+}
+
+type Comment struct {
+	AttachmentsCount int    `json:"attachments-count"`
+	AuthorFirstName  string `json:"author-firstname"`
+	AuthorID         string `json:"author-id"`
+	AuthorLastName   string `json:"author-lastname"`
+	Body             string `json:"body"`
+	CommentableID    string `json:"commentable-id"`
+	CommentableType  string `json:"commentable-type"`
+	Datetime         string `json:"datetime"`
+	HTMLBody         string `json:"html-body"`
+	ID               string `json:"id"`
+	LastChangedOn    string `json:"last-changed-on"`
+	Private          string `json:"private"`
+	ProjectID        string `json:"project-id"`
+	ProjectName      string `json:"project-name"`
+}
+
+type CommentsResponse struct {
+	Status   string    `json:"STATUS"`
+	Comments []Comment `json:"comments"`
+}
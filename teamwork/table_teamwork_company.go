@@ -0,0 +1,247 @@
+package teamwork
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableTeamworkCompany(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_company",
+		Description: "Companies (clients and the owner company) from Teamwork.com",
+		Get: &plugin.GetConfig{
+			Hydrate: getTeamworkCompany,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "id",
+					Require:    plugin.Required,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkCompanies,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the company.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the company.",
+				Transform:   transform.FromField("Name").NullIfZero(),
+			},
+			{
+				Name:        "is_owner",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether this is the site owner's company.",
+				Transform:   transform.FromField("IsOwner").NullIfZero(),
+			},
+			{
+				Name:        "address_one",
+				Type:        proto.ColumnType_STRING,
+				Description: "The first line of the company's address.",
+				Transform:   transform.FromField("AddressOne").NullIfZero(),
+			},
+			{
+				Name:        "address_two",
+				Type:        proto.ColumnType_STRING,
+				Description: "The second line of the company's address.",
+				Transform:   transform.FromField("AddressTwo").NullIfZero(),
+			},
+			{
+				Name:        "city",
+				Type:        proto.ColumnType_STRING,
+				Description: "The city of the company's address.",
+				Transform:   transform.FromField("City").NullIfZero(),
+			},
+			{
+				Name:        "state",
+				Type:        proto.ColumnType_STRING,
+				Description: "The state of the company's address.",
+				Transform:   transform.FromField("State").NullIfZero(),
+			},
+			{
+				Name:        "zip",
+				Type:        proto.ColumnType_STRING,
+				Description: "The zip or postal code of the company's address.",
+				Transform:   transform.FromField("Zip").NullIfZero(),
+			},
+			{
+				Name:        "country",
+				Type:        proto.ColumnType_STRING,
+				Description: "The country of the company's address.",
+				Transform:   transform.FromField("Country").NullIfZero(),
+			},
+			{
+				Name:        "country_code",
+				Type:        proto.ColumnType_STRING,
+				Description: "The country code of the company's address.",
+				Transform:   transform.FromField("CountryCode").NullIfZero(),
+			},
+			{
+				Name:        "phone",
+				Type:        proto.ColumnType_STRING,
+				Description: "The phone number of the company.",
+				Transform:   transform.FromField("Phone").NullIfZero(),
+			},
+			{
+				Name:        "email",
+				Type:        proto.ColumnType_STRING,
+				Description: "The primary email address of the company.",
+				Transform:   transform.FromField("EmailOne").NullIfZero(),
+			},
+			{
+				Name:        "website",
+				Type:        proto.ColumnType_STRING,
+				Description: "The website of the company.",
+				Transform:   transform.FromField("Website").NullIfZero(),
+			},
+			{
+				Name:        "industry",
+				Type:        proto.ColumnType_STRING,
+				Description: "The industry of the company.",
+				Transform:   transform.FromField("Industry").NullIfZero(),
+			},
+			{
+				Name:        "logo",
+				Type:        proto.ColumnType_STRING,
+				Description: "A URL to the company's logo.",
+				Transform:   transform.FromField("LogoURL").NullIfZero(),
+			},
+			{
+				Name:        "account_manager_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the person who manages the company's account.",
+				Transform:   transform.FromField("AccountManagerID").NullIfZero(),
+			},
+			{
+				Name:        "client_since",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the company became a client.",
+				Transform:   transform.FromField("ClientSince").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "tags",
+				Type:        proto.ColumnType_JSON,
+				Description: "Tags associated with this company.",
+				Transform:   transform.FromField("Tags").NullIfZero(),
+			},
+		},
+	}
+}
+
+func getTeamworkCompany(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a single company
+
+	plugin.Logger(ctx).Trace("Entering getTeamworkCompany()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	var company CompanyResponse
+
+	url := client.url(fmt.Sprintf("/companies/%s.json", d.EqualsQualString("id")))
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("getTeamworkCompany(): url: %s", url))
+
+	_, err = ListTeamworkItems(ctx, client, url, &company, plugin.Logger(ctx))
+	if errors.Is(err, errNotFound) {
+		plugin.Logger(ctx).Trace("Exiting getTeamworkCompany(): company not found")
+		return nil, nil
+	}
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting getTeamworkCompany()")
+	return company.Company, nil
+}
+
+func listTeamworkCompanies(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a list of companies
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkCompanies()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	url := client.url("/companies.json")
+	if params := limitPageSize(d, nil); len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkCompanies(): url: %s", url))
+
+	err = StreamTeamworkItems(ctx, client, url, plugin.Logger(ctx), func(page *CompaniesResponse) bool {
+		for _, t := range page.Companies {
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkCompanies()")
+	return nil, nil
+}
+
+type Company struct {
+	AccountManagerID string `json:"accountManagerId"`
+	AddressOne       string `json:"address_one"`
+	AddressTwo       string `json:"address_two"`
+	City             string `json:"city"`
+	ClientSince      string `json:"clientSince"`
+	Country          string `json:"country"`
+	CountryCode      string `json:"countrycode"`
+	EmailOne         string `json:"email_one"`
+	ID               string `json:"id"`
+	Industry         string `json:"industry"`
+	IsOwner          string `json:"is-owner"`
+	LogoURL          string `json:"logo-url"`
+	Name             string `json:"name"`
+	Phone            string `json:"phone"`
+	State            string `json:"state"`
+	Tags             []Tag  `json:"tags"`
+	Website          string `json:"website"`
+	Zip              string `json:"zip"`
+}
+
+type CompaniesResponse struct {
+	Status    string    `json:"STATUS"`
+	Companies []Company `json:"companies"`
+}
+
+type CompanyResponse struct {
+	Status  string  `json:"STATUS"`
+	Company Company `json:"company"`
+}
@@ -0,0 +1,348 @@
+package teamwork
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableTeamworkFile(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_file",
+		Description: "Project files from Teamwork.com",
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkFiles,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "project_id",
+					Require:    plugin.Required,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the file.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the file.",
+				Transform:   transform.FromField("Name").NullIfZero(),
+			},
+			{
+				Name:        "original_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the file when it was uploaded.",
+				Transform:   transform.FromField("OriginalName").NullIfZero(),
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the file.",
+				Transform:   transform.FromField("Description").NullIfZero(),
+			},
+			{
+				Name:        "project_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the project the file belongs to.",
+				Transform:   transform.FromQual("project_id"),
+			},
+			{
+				Name:        "category_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the file's category.",
+				Transform:   transform.FromField("CategoryID").NullIfZero(),
+			},
+			{
+				Name:        "category_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the file's category.",
+				Transform:   transform.FromField("CategoryName").NullIfZero(),
+			},
+			{
+				Name:        "size",
+				Type:        proto.ColumnType_INT,
+				Description: "The size of the latest version of the file, in bytes.",
+				Transform:   transform.FromField("Size").NullIfZero(),
+			},
+			{
+				Name:        "uploaded_by",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the person who uploaded the latest version of the file.",
+				Transform:   transform.FromField("UploadedByUserID").NullIfZero(),
+			},
+			{
+				Name:        "uploaded_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the latest version of the file was uploaded.",
+				Transform:   transform.FromField("UploadedDate").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "version",
+				Type:        proto.ColumnType_INT,
+				Description: "The version number of the latest version of the file.",
+				Transform:   transform.FromField("Version").NullIfZero(),
+			},
+			{
+				Name:        "version_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the latest version of the file.",
+				Transform:   transform.FromField("VersionID").NullIfZero(),
+			},
+			{
+				Name:        "private",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the file is private.",
+				Transform:   transform.FromField("Private"),
+			},
+			{
+				Name:        "download_url",
+				Type:        proto.ColumnType_STRING,
+				Description: "A URL to download the file.",
+				Transform:   transform.FromField("DownloadURL").NullIfZero(),
+			},
+			{
+				Name:        "tags",
+				Type:        proto.ColumnType_JSON,
+				Description: "Tags associated with this file.",
+				Transform:   transform.FromField("Tags").NullIfZero(),
+			},
+		},
+	}
+}
+
+func tableTeamworkFileVersion(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_file_version",
+		Description: "Version history of project files from Teamwork.com",
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkFileVersions,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "project_id",
+					Require:    plugin.Required,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "file_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the file version.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "file_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the file the version belongs to.",
+				Transform:   transform.FromField("FileID").NullIfZero(),
+			},
+			{
+				Name:        "project_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the project the file belongs to.",
+				Transform:   transform.FromQual("project_id"),
+			},
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the file at this version.",
+				Transform:   transform.FromField("Name").NullIfZero(),
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of this version.",
+				Transform:   transform.FromField("Description").NullIfZero(),
+			},
+			{
+				Name:        "version",
+				Type:        proto.ColumnType_INT,
+				Description: "The version number.",
+				Transform:   transform.FromField("Version").NullIfZero(),
+			},
+			{
+				Name:        "size",
+				Type:        proto.ColumnType_INT,
+				Description: "The size of this version, in bytes.",
+				Transform:   transform.FromField("Size").NullIfZero(),
+			},
+			{
+				Name:        "uploaded_by",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the person who uploaded this version.",
+				Transform:   transform.FromField("UploadedByUserID").NullIfZero(),
+			},
+			{
+				Name:        "uploaded_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date this version was uploaded.",
+				Transform:   transform.FromField("UploadedDate").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "download_url",
+				Type:        proto.ColumnType_STRING,
+				Description: "A URL to download this version.",
+				Transform:   transform.FromField("DownloadURL").NullIfZero(),
+			},
+		},
+	}
+}
+
+func listTeamworkFiles(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a list of files in a project
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkFiles()")
+
+	files, err := listProjectFiles(ctx, d, d.EqualsQualString("project_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range files {
+		d.StreamListItem(ctx, t)
+		if d.RowsRemaining(ctx) == 0 {
+			break
+		}
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkFiles()")
+	return nil, nil
+}
+
+func listTeamworkFileVersions(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get the version history of each
+	// file in a project, or of a single file when one is given
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkFileVersions()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	fileIDs := []string{d.EqualsQualString("file_id")}
+	if fileIDs[0] == "" {
+		files, err := listProjectFiles(ctx, d, d.EqualsQualString("project_id"))
+		if err != nil {
+			return nil, err
+		}
+		fileIDs = fileIDs[:0]
+		for _, f := range files {
+			fileIDs = append(fileIDs, f.ID)
+		}
+	}
+
+	for _, fileID := range fileIDs {
+		var file FileResponse
+
+		url := client.url(fmt.Sprintf("/files/%s.json", fileID))
+
+		plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkFileVersions(): url: %s", url))
+
+		_, err = ListTeamworkItems(ctx, client, url, &file, plugin.Logger(ctx))
+		if err != nil {
+			plugin.Logger(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		for _, t := range file.File.Versions {
+			t.FileID = file.File.ID
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				plugin.Logger(ctx).Trace("Exiting listTeamworkFileVersions(): limit reached")
+				return nil, nil
+			}
+		}
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkFileVersions()")
+	return nil, nil
+}
+
+// listProjectFiles fetches the files belonging to a project.
+func listProjectFiles(ctx context.Context, d *plugin.QueryData, projectID string) ([]File, error) {
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	var files ProjectFilesResponse
+
+	url := client.url(fmt.Sprintf("/projects/%s/files.json", projectID))
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listProjectFiles(): url: %s", url))
+
+	_, err = ListTeamworkItems(ctx, client, url, &files, plugin.Logger(ctx))
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+	return files.Project.Files, nil
+}
+
+type File struct {
+	CategoryID       string        `json:"category-id"`
+	CategoryName     string        `json:"category-name"`
+	Description      string        `json:"description"`
+	DownloadURL      string        `json:"download-URL"`
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	OriginalName     string        `json:"original-name"`
+	Private          string        `json:"private"`
+	Size             json.Number   `json:"size"`
+	Tags             []Tag         `json:"tags"`
+	UploadedByUserID string        `json:"uploaded-by-user-id"`
+	UploadedDate     string        `json:"uploaded-date"`
+	Version          json.Number   `json:"version"`
+	VersionID        string        `json:"version-id"`
+	Versions         []FileVersion `json:"versions"`
+}
+
+type FileVersion struct {
+	Description      string      `json:"description"`
+	DownloadURL      string      `json:"download-URL"`
+	FileID           string      `json:"-"`
+	ID               string      `json:"id"`
+	Name             string      `json:"name"`
+	Size             json.Number `json:"size"`
+	UploadedByUserID string      `json:"uploaded-by-user-id"`
+	UploadedDate     string      `json:"uploaded-date"`
+	Version          json.Number `json:"version"`
+}
+
+type ProjectFilesResponse struct {
+	Status  string `json:"STATUS"`
+	Project struct {
+		Files []File `json:"files"`
+	} `json:"project"`
+}
+
+type FileResponse struct {
+	Status string `json:"STATUS"`
+	File   File   `json:"file"`
+}
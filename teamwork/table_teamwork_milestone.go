@@ -0,0 +1,243 @@
+package teamwork
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// milestoneStatuses are the status qualifiers that can be pushed down to the
+// milestones endpoint's find filter.
+var milestoneStatuses = map[string]bool{
+	"upcoming":  true,
+	"late":      true,
+	"completed": true,
+}
+
+func tableTeamworkMilestone(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_milestone",
+		Description: "Milestones from Teamwork.com",
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkMilestones,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "project_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "status",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the milestone.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the milestone.",
+				Transform:   transform.FromField("Title").NullIfZero(),
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the milestone.",
+				Transform:   transform.FromField("Description").NullIfZero(),
+			},
+			{
+				Name:        "project_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the project the milestone belongs to.",
+				Transform:   transform.FromField("ProjectID").NullIfZero(),
+			},
+			{
+				Name:        "project_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the project the milestone belongs to.",
+				Transform:   transform.FromField("ProjectName").NullIfZero(),
+			},
+			{
+				Name:        "status",
+				Type:        proto.ColumnType_STRING,
+				Description: "The status of the milestone: upcoming, late or completed.",
+				Transform:   transform.From(milestoneStatus),
+			},
+			{
+				Name:        "deadline",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The deadline of the milestone.",
+				Transform:   transform.FromField("Deadline").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "completed",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the milestone is completed.",
+				Transform:   transform.FromField("Completed"),
+			},
+			{
+				Name:        "completed_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the milestone was completed.",
+				Transform:   transform.FromField("CompletedOn").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "responsible_party_ids",
+				Type:        proto.ColumnType_JSON,
+				Description: "The IDs of the people responsible for the milestone.",
+				Transform: transform.FromField("ResponsiblePartyIDs").
+					Transform(transformCommaSeparatedList),
+			},
+			{
+				Name:        "tasklist_ids",
+				Type:        proto.ColumnType_JSON,
+				Description: "The IDs of the tasklists attached to the milestone.",
+				Transform:   transform.From(milestoneTasklistIDs),
+			},
+			{
+				Name:        "reminder",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether a reminder is sent for the milestone.",
+				Transform:   transform.FromField("Reminder"),
+			},
+			{
+				Name:        "private",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the milestone is private.",
+				Transform:   transform.FromField("Private"),
+			},
+			{
+				Name:        "created_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the milestone was created.",
+				Transform:   transform.FromField("CreatedOn").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "last_changed_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date of the last change to the milestone.",
+				Transform:   transform.FromField("LastChangedOn").Transform(transformTeamworkDate),
+			},
+		},
+	}
+}
+
+func listTeamworkMilestones(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a list of milestones, scoped to a
+	// project and filtered by status when they are given
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkMilestones()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	path := "/milestones.json"
+	if projectID := d.EqualsQualString("project_id"); projectID != "" {
+		path = fmt.Sprintf("/projects/%s/milestones.json", projectID)
+	}
+
+	params := url.Values{}
+	params.Set("find", "all")
+	params.Set("showTaskLists", "true")
+	if status := d.EqualsQualString("status"); milestoneStatuses[status] {
+		params.Set("find", status)
+	}
+	endpoint := client.url(path) + "?" + limitPageSize(d, params).Encode()
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkMilestones(): url: %s", endpoint))
+
+	err = StreamTeamworkItems(ctx, client, endpoint, plugin.Logger(ctx), func(page *MilestonesResponse) bool {
+		for _, t := range page.Milestones {
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkMilestones()")
+	return nil, nil
+}
+
+// milestoneStatus returns the status reported by the API, deriving it from the
+// completed flag and deadline when the API omits it.
+func milestoneStatus(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	milestone, ok := d.HydrateItem.(Milestone)
+	if !ok {
+		return nil, nil
+	}
+	if milestone.Status != "" {
+		return milestone.Status, nil
+	}
+	if milestone.Completed {
+		return "completed", nil
+	}
+	deadline, err := parseTeamworkDate(milestone.Deadline)
+	if err == nil && deadline != nil && deadline.Before(time.Now().Truncate(24*time.Hour)) {
+		return "late", nil
+	}
+	return "upcoming", nil
+}
+
+// milestoneTasklistIDs extracts the IDs of the tasklists attached to a milestone.
+func milestoneTasklistIDs(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	milestone, ok := d.HydrateItem.(Milestone)
+	if !ok || len(milestone.Tasklists) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(milestone.Tasklists))
+	for _, tasklist := range milestone.Tasklists {
+		ids = append(ids, tasklist.ID)
+	}
+	return ids, nil
+}
+
+type Milestone struct {
+	Completed           bool   `json:"completed"`
+	CompletedOn         string `json:"completed-on"`
+	CreatedOn           string `json:"created-on"`
+	Deadline            string `json:"deadline"`
+	Description         string `json:"description"`
+	ID                  string `json:"id"`
+	LastChangedOn       string `json:"last-changed-on"`
+	Private             bool   `json:"private"`
+	ProjectID           string `json:"project-id"`
+	ProjectName         string `json:"project-name"`
+	Reminder            bool   `json:"reminder"`
+	ResponsiblePartyIDs string `json:"responsible-party-ids"`
+	Status              string `json:"status"`
+	Title               string `json:"title"`
+	Tasklists           []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"tasklists"`
+}
+
+type MilestonesResponse struct {
+	Status     string      `json:"STATUS"`
+	Milestones []Milestone `json:"milestones"`
+}
@@ -0,0 +1,276 @@
+package teamwork
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableTeamworkPerson(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_person",
+		Description: "People (users) from Teamwork.com",
+		Get: &plugin.GetConfig{
+			Hydrate: getTeamworkPerson,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "id",
+					Require:    plugin.Required,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkPeople,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "company_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "project_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the person.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "first_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The first name of the person.",
+				Transform:   transform.FromField("FirstName").NullIfZero(),
+			},
+			{
+				Name:        "last_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The last name of the person.",
+				Transform:   transform.FromField("LastName").NullIfZero(),
+			},
+			{
+				Name:        "email",
+				Type:        proto.ColumnType_STRING,
+				Description: "The email address of the person.",
+				Transform:   transform.FromField("EmailAddress").NullIfZero(),
+			},
+			{
+				Name:        "user_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The user name of the person.",
+				Transform:   transform.FromField("UserName").NullIfZero(),
+			},
+			{
+				Name:        "title",
+				Type:        proto.ColumnType_STRING,
+				Description: "The job title of the person.",
+				Transform:   transform.FromField("Title").NullIfZero(),
+			},
+			{
+				Name:        "company_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the company the person belongs to.",
+				Transform:   transform.FromField("CompanyID").NullIfZero(),
+			},
+			{
+				Name:        "company_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the company the person belongs to.",
+				Transform:   transform.FromField("CompanyName").NullIfZero(),
+			},
+			{
+				Name:        "project_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the project used to scope the query, if any.",
+				Transform:   transform.FromQual("project_id"),
+			},
+			{
+				Name:        "user_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of user: account, collaborator or contact.",
+				Transform:   transform.FromField("UserType").NullIfZero(),
+			},
+			{
+				Name:        "administrator",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the person is a site administrator.",
+				Transform:   transform.FromField("Administrator"),
+			},
+			{
+				Name:        "site_owner",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the person is the site owner.",
+				Transform:   transform.FromField("SiteOwner"),
+			},
+			{
+				Name:        "deleted",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the person has been deleted.",
+				Transform:   transform.FromField("Deleted"),
+			},
+			{
+				Name:        "last_login",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the person last logged in.",
+				Transform:   transform.FromField("LastLogin").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "created_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the person was created.",
+				Transform:   transform.FromField("CreatedAt").NullIfZero(),
+			},
+			{
+				Name:        "last_changed_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date of the last change to the person.",
+				Transform:   transform.FromField("LastChangedOn").NullIfZero(),
+			},
+			{
+				Name:        "timezone",
+				Type:        proto.ColumnType_STRING,
+				Description: "The timezone of the person.",
+				Transform:   transform.FromField("Localization.TimezoneJavaRefCode").NullIfZero(),
+			},
+			{
+				Name:        "avatar_url",
+				Type:        proto.ColumnType_STRING,
+				Description: "A URL to the person's avatar.",
+				Transform:   transform.FromField("AvatarURL").NullIfZero(),
+			},
+			{
+				Name:        "permissions",
+				Type:        proto.ColumnType_JSON,
+				Description: "The permissions of the person.",
+				Transform:   transform.FromField("Permissions").NullIfZero(),
+			},
+		},
+	}
+}
+
+func getTeamworkPerson(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a single person
+
+	plugin.Logger(ctx).Trace("Entering getTeamworkPerson()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	var person PersonResponse
+
+	url := client.url(fmt.Sprintf("/people/%s.json", d.EqualsQualString("id")))
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("getTeamworkPerson(): url: %s", url))
+
+	_, err = ListTeamworkItems(ctx, client, url, &person, plugin.Logger(ctx))
+	if errors.Is(err, errNotFound) {
+		plugin.Logger(ctx).Trace("Exiting getTeamworkPerson(): person not found")
+		return nil, nil
+	}
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting getTeamworkPerson()")
+	return person.Person, nil
+}
+
+func listTeamworkPeople(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a list of people, scoped to a
+	// project or company when one is given
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkPeople()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	path := "/people.json"
+	if projectID := d.EqualsQualString("project_id"); projectID != "" {
+		path = fmt.Sprintf("/projects/%s/people.json", projectID)
+	} else if companyID := d.EqualsQualString("company_id"); companyID != "" {
+		path = fmt.Sprintf("/companies/%s/people.json", companyID)
+	}
+	url := client.url(path)
+	if params := limitPageSize(d, nil); len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkPeople(): url: %s", url))
+
+	err = StreamTeamworkItems(ctx, client, url, plugin.Logger(ctx), func(page *PeopleResponse) bool {
+		for _, t := range page.People {
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkPeople()")
+	return nil, nil
+}
+
+type Person struct {
+	Administrator bool      `json:"administrator"`
+	AvatarURL     string    `json:"avatar-url"`
+	CompanyID     string    `json:"company-id"`
+	CompanyName   string    `json:"company-name"`
+	CreatedAt     time.Time `json:"created-at"`
+	Deleted       bool      `json:"deleted"`
+	EmailAddress  string    `json:"email-address"`
+	FirstName     string    `json:"first-name"`
+	ID            string    `json:"id"`
+	LastChangedOn time.Time `json:"last-changed-on"`
+	LastLogin     string    `json:"last-login"`
+	LastName      string    `json:"last-name"`
+	Permissions   any       `json:"permissions"`
+	SiteOwner     bool      `json:"site-owner"`
+	Title         string    `json:"title"`
+	UserName      string    `json:"user-name"`
+	UserType      string    `json:"user-type"`
+	Localization  struct {
+		Timezone            string `json:"timezone"`
+		TimezoneJavaRefCode string `json:"timezoneJavaRefCode"`
+	} `json:"localization"`
+}
+
+type PeopleResponse struct {
+	Status string   `json:"STATUS"`
+	People []Person `json:"people"`
+}
+
+type PersonResponse struct {
+	Status string `json:"STATUS"`
+	Person Person `json:"person"`
+}
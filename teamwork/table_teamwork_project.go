@@ -2,7 +2,11 @@ package teamwork
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
@@ -14,7 +18,6 @@ func tableTeamworkProject(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "teamwork_project",
 		Description: "Projects from Teamwork.com",
-		/*  // TODO - Implement GetConfig
 		Get: &plugin.GetConfig{
 			Hydrate: getTeamworkProject,
 			KeyColumns: []*plugin.KeyColumn{
@@ -25,12 +28,46 @@ func tableTeamworkProject(ctx context.Context) *plugin.Table {
 				},
 			},
 		},
-		*/
 		List: &plugin.ListConfig{
 			Hydrate: listTeamworkProjects,
 			KeyColumns: []*plugin.KeyColumn{
 				{
-					Name:       "id",
+					Name:       "status",
+					Operators:  []string{"=", "<>"},
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "company_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "category_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "tags",
+					Operators:  []string{"@>"},
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "created_on",
+					Operators:  []string{">", ">=", "="},
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "last_changed_on",
+					Operators:  []string{">", ">=", "="},
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "starred",
+					Operators:  []string{"=", "<>"},
 					Require:    plugin.Optional,
 					CacheMatch: "exact",
 				},
@@ -57,8 +94,14 @@ func tableTeamworkProject(ctx context.Context) *plugin.Table {
 			},
 			{
 				Name:        "start_date",
-				Type:        proto.ColumnType_STRING,
+				Type:        proto.ColumnType_TIMESTAMP,
 				Description: "The start date of the project.",
+				Transform:   transform.FromField("StartDate").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "start_date_raw",
+				Type:        proto.ColumnType_STRING,
+				Description: "The start date of the project, as returned by Teamwork (YYYYMMDD, or RFC 3339 with api_version v3).",
 				Transform:   transform.FromField("StartDate").NullIfZero(),
 			},
 			{
@@ -326,8 +369,14 @@ func tableTeamworkProject(ctx context.Context) *plugin.Table {
 			},
 			{
 				Name:        "end_date",
-				Type:        proto.ColumnType_STRING,
+				Type:        proto.ColumnType_TIMESTAMP,
 				Description: "The projected end date of the project.",
+				Transform:   transform.FromField("EndDate").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "end_date_raw",
+				Type:        proto.ColumnType_STRING,
+				Description: "The projected end date of the project, as returned by Teamwork (YYYYMMDD, or RFC 3339 with api_version v3).",
 				Transform:   transform.FromField("EndDate").NullIfZero(),
 			},
 			{
@@ -484,7 +533,6 @@ func tableTeamworkProject(ctx context.Context) *plugin.Table {
 	}
 }
 
-/*
 func getTeamworkProject(
 	ctx context.Context,
 	d *plugin.QueryData,
@@ -494,27 +542,34 @@ func getTeamworkProject(
 
 	plugin.Logger(ctx).Trace("Entering getTeamworkProject()")
 
-	config := GetConfig(d.Connection)
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+	if apiVersion(client.config) == "v3" {
+		return getTeamworkProjectV3(ctx, d, client)
+	}
 
 	var project ProjectResponse
 
-	url := fmt.Sprintf("https://teamwork.%s.com", *config.Domain)
-	url = fmt.Sprintf("%s/projects/%s.json", url, d.EqualsQualString("id"))
+	url := client.url(fmt.Sprintf("/projects/%s.json", d.EqualsQualString("id")))
 
 	plugin.Logger(ctx).Trace(fmt.Sprintf("getTeamworkProject(): url: %s", url))
 
-	_, err := ListTeamworkItems(*config.APIKey, url, &project, plugin.Logger(ctx))
+	_, err = ListTeamworkItems(ctx, client, url, &project, plugin.Logger(ctx))
+	if errors.Is(err, errNotFound) {
+		plugin.Logger(ctx).Trace("Exiting getTeamworkProject(): project not found")
+		return nil, nil
+	}
 	if err != nil {
 		plugin.Logger(ctx).Error(err.Error())
 		return nil, err
 	}
 
-	plugin.Logger(ctx).Info(fmt.Sprintf("getTeamworkProject(): project %+v", project))
-
 	plugin.Logger(ctx).Trace("Exiting getTeamworkProject()")
 	return project.Project, nil
 }
-*/
 
 func listTeamworkProjects(
 	ctx context.Context,
@@ -525,28 +580,242 @@ func listTeamworkProjects(
 
 	plugin.Logger(ctx).Trace("Entering listTeamworkProjects()")
 
-	config := GetConfig(d.Connection)
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+	if apiVersion(client.config) == "v3" {
+		return listTeamworkProjectsV3(ctx, d, client)
+	}
+
+	endpoint, err := withQuery(client.url("/projects.json"), limitPageSize(d, projectParams(d)))
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkProjects(): url: %s", endpoint))
 
-	var projects ProjectsResponse
+	err = StreamTeamworkItems(ctx, client, endpoint, plugin.Logger(ctx), func(page *ProjectsResponse) bool {
+		for _, t := range page.Projects {
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
 
-	url := fmt.Sprintf("https://teamwork.%s.com", *config.Domain)
-	url = fmt.Sprintf("%s/projects.json", url)
+	plugin.Logger(ctx).Trace("Exiting listTeamworkProjects()")
+	return nil, nil
+}
 
-	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkProjects(): url: %s", url))
+// projectStatuses are the values of the status parameter of the projects endpoint,
+// keyed by the status column values they select. The endpoint also accepts filters
+// such as LATE and COMPLETED, but those are not values the status column can hold.
+var projectStatuses = map[string]string{
+	"active":   "ACTIVE",
+	"archived": "ARCHIVED",
+}
 
-	_, err := ListTeamworkItems(*config.APIKey, url, &projects, plugin.Logger(ctx))
+// afterFilterMargin is how far before a created_on or last_changed_on qualifier the
+// "after" filters of the projects endpoints are set. The API may compare strictly
+// and in the site's timezone rather than UTC, so the filter starts a day early to
+// keep rows at the boundary, and the exact qualifier is checked against every row
+// returned.
+const afterFilterMargin = 24 * time.Hour
+
+// projectParams maps the qualifiers of a projects query to query parameters of the
+// projects endpoint. Qualifiers the API can only partly apply, such as an upper bound
+// on created_on, are still checked against every row returned.
+func projectParams(d *plugin.QueryData) url.Values {
+	params := url.Values{}
+
+	// The API returns only active projects unless asked for another status
+	if quals, ok := d.Quals["status"]; ok {
+		params.Set("status", "ALL")
+		for _, q := range quals.Quals {
+			if status, ok := projectStatuses[strings.ToLower(q.Value.GetStringValue())]; ok && q.Operator == "=" {
+				params.Set("status", status)
+			}
+		}
+	}
+
+	if companyID := d.EqualsQualString("company_id"); companyID != "" {
+		params.Set("companyId", companyID)
+	}
+	if categoryID := d.EqualsQualString("category_id"); categoryID != "" {
+		params.Set("catId", categoryID)
+	}
+
+	if ids := projectTagIDs(d); len(ids) > 0 {
+		params.Set("projectTagIds", strings.Join(ids, ","))
+		params.Set("matchAllTags", "true")
+	}
+
+	if quals, ok := d.Quals["created_on"]; ok {
+		for _, q := range quals.Quals {
+			createdOn := q.Value.GetTimestampValue().AsTime().Add(-afterFilterMargin)
+			params.Set("createdAfterDate", createdOn.Format("20060102"))
+			params.Set("createdAfterTime", createdOn.Format("15:04"))
+		}
+	}
+	if quals, ok := d.Quals["last_changed_on"]; ok {
+		for _, q := range quals.Quals {
+			updatedOn := q.Value.GetTimestampValue().AsTime().Add(-afterFilterMargin)
+			params.Set("updatedAfterDate", updatedOn.Format("20060102150405"))
+		}
+	}
+
+	if quals, ok := d.Quals["starred"]; ok {
+		for _, q := range quals.Quals {
+			if q.Value.GetBoolValue() == (q.Operator == "=") {
+				params.Set("onlyStarredProjects", "true")
+			}
+		}
+	}
+
+	return params
+}
+
+// projectParamsV3 maps the qualifiers of a projects query to query parameters of the
+// v3 projects endpoint, which has no filter on created_on.
+func projectParamsV3(d *plugin.QueryData) url.Values {
+	params := url.Values{}
+
+	// The API returns only active projects unless asked for another status
+	if quals, ok := d.Quals["status"]; ok {
+		params.Set("projectStatuses", "active,archived")
+		for _, q := range quals.Quals {
+			if _, ok := projectStatuses[strings.ToLower(q.Value.GetStringValue())]; ok && q.Operator == "=" {
+				params.Set("projectStatuses", strings.ToLower(q.Value.GetStringValue()))
+			}
+		}
+	}
+
+	if companyID := d.EqualsQualString("company_id"); companyID != "" {
+		params.Set("companyIds", companyID)
+	}
+	if categoryID := d.EqualsQualString("category_id"); categoryID != "" {
+		params.Set("projectCategoryIds", categoryID)
+	}
+	if ids := projectTagIDs(d); len(ids) > 0 {
+		params.Set("projectTagIds", strings.Join(ids, ","))
+		params.Set("matchAllTags", "true")
+	}
+
+	if quals, ok := d.Quals["last_changed_on"]; ok {
+		for _, q := range quals.Quals {
+			updatedOn := q.Value.GetTimestampValue().AsTime().Add(-afterFilterMargin)
+			params.Set("updatedAfter", updatedOn.Format(time.RFC3339))
+		}
+	}
+
+	if quals, ok := d.Quals["starred"]; ok {
+		for _, q := range quals.Quals {
+			if q.Value.GetBoolValue() == (q.Operator == "=") {
+				params.Set("onlyStarredProjects", "true")
+			}
+		}
+	}
+
+	return params
+}
+
+// projectTagIDs returns the tag IDs selected by a tags @> '[{"id": 1234}]' qualifier,
+// which matches projects with all of the given tags. Qualifiers naming tags by
+// anything other than ID are left to be checked against every row returned.
+func projectTagIDs(d *plugin.QueryData) []string {
+	quals, ok := d.Quals["tags"]
+	if !ok {
+		return nil
+	}
+	for _, q := range quals.Quals {
+		var tags []Tag
+		if err := json.Unmarshal([]byte(q.Value.GetJsonbValue()), &tags); err != nil {
+			continue
+		}
+		var ids []string
+		for _, tag := range tags {
+			if tag.ID != "" {
+				ids = append(ids, tag.ID.String())
+			}
+		}
+		if len(ids) > 0 && len(ids) == len(tags) {
+			return ids
+		}
+	}
+	return nil
+}
+
+// v3ProjectIncludes are the related resources side-loaded with v3 projects.
+const v3ProjectIncludes = "companies,projectCategories,tags"
+
+func getTeamworkProjectV3(
+	ctx context.Context,
+	d *plugin.QueryData,
+	client *teamworkClient,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API v3 and get a single project
+
+	plugin.Logger(ctx).Trace("Entering getTeamworkProjectV3()")
+
+	url := client.url(fmt.Sprintf("%s/projects/%s.json?include=%s",
+		v3PathPrefix, d.EqualsQualString("id"), v3ProjectIncludes))
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("getTeamworkProjectV3(): url: %s", url))
+
+	projects, included, err := ListTeamworkItemsV3[V3Project](
+		ctx, client, url, "project", plugin.Logger(ctx))
+	if errors.Is(err, errNotFound) || (err == nil && len(projects) == 0) {
+		plugin.Logger(ctx).Trace("Exiting getTeamworkProjectV3(): project not found")
+		return nil, nil
+	}
 	if err != nil {
 		plugin.Logger(ctx).Error(err.Error())
 		return nil, err
 	}
 
-	plugin.Logger(ctx).Info(fmt.Sprintf("listTeamworkProjects(): projects %+v", projects))
+	plugin.Logger(ctx).Trace("Exiting getTeamworkProjectV3()")
+	return projects[0].toProject(included), nil
+}
+
+func listTeamworkProjectsV3(
+	ctx context.Context,
+	d *plugin.QueryData,
+	client *teamworkClient,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API v3 and get a list of projects
 
-	for _, t := range projects.Projects {
-		d.StreamListItem(ctx, t)
+	plugin.Logger(ctx).Trace("Entering listTeamworkProjectsV3()")
+
+	params := limitPageSize(d, projectParamsV3(d))
+	params.Set("include", v3ProjectIncludes)
+	endpoint := client.url(v3PathPrefix+"/projects.json") + "?" + params.Encode()
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkProjectsV3(): url: %s", endpoint))
+
+	err := StreamTeamworkItemsV3(ctx, client, endpoint, "projects", plugin.Logger(ctx),
+		func(projects []V3Project, included V3Included) bool {
+			for _, t := range projects {
+				d.StreamListItem(ctx, t.toProject(included))
+				if d.RowsRemaining(ctx) == 0 {
+					return false
+				}
+			}
+			return true
+		})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
 	}
 
-	plugin.Logger(ctx).Trace("Exiting listTeamworkProjects()")
+	plugin.Logger(ctx).Trace("Exiting listTeamworkProjectsV3()")
 	return nil, nil
 }
 
@@ -651,3 +920,88 @@ type ProjectResponse struct {
 	Status  string  `json:"STATUS"`
 	Project Project `json:"project"`
 }
+
+// V3Project is a project as returned by the Teamwork API v3.
+type V3Project struct {
+	Category    *V3Ref  `json:"category"`
+	Company     *V3Ref  `json:"company"`
+	CreatedAt   string  `json:"createdAt"`
+	Description string  `json:"description"`
+	EndAt       string  `json:"endAt"`
+	ID          int     `json:"id"`
+	IsBillable  bool    `json:"isBillable"`
+	IsStarred   bool    `json:"isStarred"`
+	Logo        string  `json:"logo"`
+	Name        string  `json:"name"`
+	StartAt     string  `json:"startAt"`
+	Status      string  `json:"status"`
+	SubStatus   string  `json:"subStatus"`
+	Tags        []V3Ref `json:"tags"`
+	Type        string  `json:"type"`
+	UpdatedAt   string  `json:"updatedAt"`
+}
+
+// toProject converts a v3 project, and the resources side-loaded with it, into the
+// v1 shape used by the teamwork_project columns.
+func (p V3Project) toProject(included V3Included) Project {
+	project := Project{
+		Description: p.Description,
+		ID:          fmt.Sprint(p.ID),
+		IsBillable:  p.IsBillable,
+		Logo:        p.Logo,
+		Name:        p.Name,
+		Starred:     p.IsStarred,
+		Status:      p.Status,
+		SubStatus:   p.SubStatus,
+		Type:        p.Type,
+	}
+
+	if t, err := parseTeamworkDate(p.CreatedAt); err == nil && t != nil {
+		project.CreatedOn = *t
+	}
+	if t, err := parseTeamworkDate(p.UpdatedAt); err == nil && t != nil {
+		project.LastChangedOn = *t
+	}
+	// Kept as returned for the *_raw columns; the date columns parse either format
+	project.StartDate = p.StartAt
+	project.EndDate = p.EndAt
+
+	// The refs carry the IDs; the rest comes from the included resources, which
+	// the response may leave out
+	if p.Company != nil {
+		project.Company.ID = fmt.Sprint(p.Company.ID)
+	}
+	var company struct {
+		IsOwner bool   `json:"isOwner"`
+		Name    string `json:"name"`
+	}
+	if ok, err := included.Decode(p.Company, &company); ok && err == nil {
+		project.Company.IsOwner = fmt.Sprint(company.IsOwner)
+		project.Company.Name = company.Name
+	}
+
+	if p.Category != nil {
+		project.Category.ID = fmt.Sprint(p.Category.ID)
+	}
+	var category struct {
+		Color    string `json:"color"`
+		Name     string `json:"name"`
+		ParentID *int   `json:"parentId"`
+	}
+	if ok, err := included.Decode(p.Category, &category); ok && err == nil {
+		project.Category.Color = category.Color
+		project.Category.Name = category.Name
+		if category.ParentID != nil {
+			project.Category.ParentID = fmt.Sprint(*category.ParentID)
+		}
+	}
+
+	for i := range p.Tags {
+		var tag Tag
+		if ok, err := included.Decode(&p.Tags[i], &tag); ok && err == nil {
+			project.Tags = append(project.Tags, tag)
+		}
+	}
+
+	return project
+}
@@ -0,0 +1,219 @@
+package teamwork
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// queryData returns query data holding the given qualifiers.
+func queryData(columnQuals map[string][]*quals.Qual) *plugin.QueryData {
+	d := &plugin.QueryData{
+		Quals:       plugin.KeyColumnQualMap{},
+		EqualsQuals: map[string]*proto.QualValue{},
+	}
+	for column, qs := range columnQuals {
+		d.Quals[column] = &plugin.KeyColumnQuals{Name: column, Quals: qs}
+		for _, q := range qs {
+			if q.Operator == "=" {
+				d.EqualsQuals[column] = q.Value
+			}
+		}
+	}
+	return d
+}
+
+func qualString(s string) *proto.QualValue {
+	return &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: s}}
+}
+
+func qualBool(b bool) *proto.QualValue {
+	return &proto.QualValue{Value: &proto.QualValue_BoolValue{BoolValue: b}}
+}
+
+func qualJSONB(s string) *proto.QualValue {
+	return &proto.QualValue{Value: &proto.QualValue_JsonbValue{JsonbValue: s}}
+}
+
+func qualTimestamp(s string) *proto.QualValue {
+	ts, _ := time.Parse(time.RFC3339, s)
+	return &proto.QualValue{Value: &proto.QualValue_TimestampValue{TimestampValue: timestamppb.New(ts)}}
+}
+
+func TestProjectParams(t *testing.T) {
+	for _, test := range []struct {
+		Name  string
+		quals map[string][]*quals.Qual
+		want  url.Values
+	}{
+		{"none", nil, url.Values{}},
+		{
+			"status",
+			map[string][]*quals.Qual{"status": {{Column: "status", Operator: "=", Value: qualString("archived")}}},
+			url.Values{"status": {"ARCHIVED"}},
+		},
+		{
+			"status the column cannot hold",
+			map[string][]*quals.Qual{"status": {{Column: "status", Operator: "=", Value: qualString("late")}}},
+			url.Values{"status": {"ALL"}},
+		},
+		{
+			"status not equal",
+			map[string][]*quals.Qual{"status": {{Column: "status", Operator: "<>", Value: qualString("active")}}},
+			url.Values{"status": {"ALL"}},
+		},
+		{
+			"company and category",
+			map[string][]*quals.Qual{
+				"company_id":  {{Column: "company_id", Operator: "=", Value: qualString("71584")}},
+				"category_id": {{Column: "category_id", Operator: "=", Value: qualString("12")}},
+			},
+			url.Values{"companyId": {"71584"}, "catId": {"12"}},
+		},
+		{
+			"tags",
+			map[string][]*quals.Qual{"tags": {{Column: "tags", Operator: "@>", Value: qualJSONB(`[{"id": 3}, {"id": 5}]`)}}},
+			url.Values{"projectTagIds": {"3,5"}, "matchAllTags": {"true"}},
+		},
+		{
+			"tags by name",
+			map[string][]*quals.Qual{"tags": {{Column: "tags", Operator: "@>", Value: qualJSONB(`[{"name": "Priority"}]`)}}},
+			url.Values{},
+		},
+		{
+			"dates",
+			map[string][]*quals.Qual{
+				"created_on":      {{Column: "created_on", Operator: ">=", Value: qualTimestamp("2024-01-02T03:04:05Z")}},
+				"last_changed_on": {{Column: "last_changed_on", Operator: ">", Value: qualTimestamp("2024-02-03T04:05:06Z")}},
+			},
+			url.Values{
+				"createdAfterDate": {"20240101"},
+				"createdAfterTime": {"03:04"},
+				"updatedAfterDate": {"20240202040506"},
+			},
+		},
+		{
+			"dates equal",
+			map[string][]*quals.Qual{
+				"created_on":      {{Column: "created_on", Operator: "=", Value: qualTimestamp("2024-01-02T03:04:05Z")}},
+				"last_changed_on": {{Column: "last_changed_on", Operator: "=", Value: qualTimestamp("2024-02-03T04:05:06Z")}},
+			},
+			url.Values{
+				"createdAfterDate": {"20240101"},
+				"createdAfterTime": {"03:04"},
+				"updatedAfterDate": {"20240202040506"},
+			},
+		},
+		{
+			"last changed on or after",
+			map[string][]*quals.Qual{"last_changed_on": {{Column: "last_changed_on", Operator: ">=", Value: qualTimestamp("2024-03-01T00:00:00Z")}}},
+			url.Values{"updatedAfterDate": {"20240229000000"}},
+		},
+		{
+			"starred",
+			map[string][]*quals.Qual{"starred": {{Column: "starred", Operator: "=", Value: qualBool(true)}}},
+			url.Values{"onlyStarredProjects": {"true"}},
+		},
+		{
+			"not starred",
+			map[string][]*quals.Qual{"starred": {{Column: "starred", Operator: "=", Value: qualBool(false)}}},
+			url.Values{},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := projectParams(queryData(test.quals)); got.Encode() != test.want.Encode() {
+				t.Errorf("unexpected params: got %v, want %v", got.Encode(), test.want.Encode())
+			}
+		})
+	}
+}
+
+func TestProjectParamsV3(t *testing.T) {
+	for _, test := range []struct {
+		Name  string
+		quals map[string][]*quals.Qual
+		want  url.Values
+	}{
+		{"none", nil, url.Values{}},
+		{
+			"status",
+			map[string][]*quals.Qual{"status": {{Column: "status", Operator: "=", Value: qualString("archived")}}},
+			url.Values{"projectStatuses": {"archived"}},
+		},
+		{
+			"status not equal",
+			map[string][]*quals.Qual{"status": {{Column: "status", Operator: "<>", Value: qualString("active")}}},
+			url.Values{"projectStatuses": {"active,archived"}},
+		},
+		{
+			"company, category and tags",
+			map[string][]*quals.Qual{
+				"company_id":  {{Column: "company_id", Operator: "=", Value: qualString("71584")}},
+				"category_id": {{Column: "category_id", Operator: "=", Value: qualString("12")}},
+				"tags":        {{Column: "tags", Operator: "@>", Value: qualJSONB(`[{"id": 3}]`)}},
+			},
+			url.Values{
+				"companyIds":         {"71584"},
+				"projectCategoryIds": {"12"},
+				"projectTagIds":      {"3"},
+				"matchAllTags":       {"true"},
+			},
+		},
+		{
+			"last changed and starred",
+			map[string][]*quals.Qual{
+				"last_changed_on": {{Column: "last_changed_on", Operator: ">", Value: qualTimestamp("2024-02-03T04:05:06Z")}},
+				"starred":         {{Column: "starred", Operator: "=", Value: qualBool(true)}},
+			},
+			url.Values{"updatedAfter": {"2024-02-02T04:05:06Z"}, "onlyStarredProjects": {"true"}},
+		},
+		{
+			"last changed equal",
+			map[string][]*quals.Qual{"last_changed_on": {{Column: "last_changed_on", Operator: "=", Value: qualTimestamp("2024-02-03T04:05:06Z")}}},
+			url.Values{"updatedAfter": {"2024-02-02T04:05:06Z"}},
+		},
+		{
+			"last changed on or after",
+			map[string][]*quals.Qual{"last_changed_on": {{Column: "last_changed_on", Operator: ">=", Value: qualTimestamp("2024-03-01T00:00:00Z")}}},
+			url.Values{"updatedAfter": {"2024-02-29T00:00:00Z"}},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := projectParamsV3(queryData(test.quals)); got.Encode() != test.want.Encode() {
+				t.Errorf("unexpected params: got %v, want %v", got.Encode(), test.want.Encode())
+			}
+		})
+	}
+}
+
+func TestV3ProjectToProject(t *testing.T) {
+	p := V3Project{
+		ID:       42,
+		Category: &V3Ref{ID: 12, Type: "projectCategories"},
+		Company:  &V3Ref{ID: 71584, Type: "companies"},
+	}
+
+	// The IDs come from the refs even when the related resources are not included
+	got := p.toProject(V3Included{})
+	if got.Company.ID != "71584" || got.Category.ID != "12" {
+		t.Errorf("unexpected IDs: got company %q and category %q, want %q and %q", got.Company.ID, got.Category.ID, "71584", "12")
+	}
+	if got.Company.Name != "" || got.Category.Name != "" {
+		t.Errorf("unexpected names: got company %q and category %q, want none", got.Company.Name, got.Category.Name)
+	}
+
+	included := V3Included{
+		"companies":         {"71584": json.RawMessage(`{"id": 71584, "name": "Acme"}`)},
+		"projectCategories": {"12": json.RawMessage(`{"id": 12, "name": "Clients", "color": "#f00"}`)},
+	}
+	got = p.toProject(included)
+	if got.Company.Name != "Acme" || got.Category.Name != "Clients" || got.Category.Color != "#f00" {
+		t.Errorf("unexpected included fields: got %+v and %+v", got.Company, got.Category)
+	}
+}
@@ -0,0 +1,249 @@
+package teamwork
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableTeamworkTask(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_task",
+		Description: "Tasks from Teamwork.com",
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkTasks,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "project_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "tasklist_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the task.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name (content) of the task.",
+				Transform:   transform.FromField("Content").NullIfZero(),
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the task.",
+				Transform:   transform.FromField("Description").NullIfZero(),
+			},
+			{
+				Name:        "project_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the project the task belongs to.",
+				Transform:   transform.FromField("ProjectID").NullIfZero(),
+			},
+			{
+				Name:        "project_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the project the task belongs to.",
+				Transform:   transform.FromField("ProjectName").NullIfZero(),
+			},
+			{
+				Name:        "tasklist_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the tasklist the task belongs to.",
+				Transform:   transform.FromField("TasklistID").NullIfZero(),
+			},
+			{
+				Name:        "tasklist_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the tasklist the task belongs to.",
+				Transform:   transform.FromField("TasklistName").NullIfZero(),
+			},
+			{
+				Name:        "parent_task_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the parent task, if this is a subtask.",
+				Transform:   transform.FromField("ParentTaskID").NullIfZero(),
+			},
+			{
+				Name:        "assignees",
+				Type:        proto.ColumnType_JSON,
+				Description: "The IDs of the people the task is assigned to.",
+				Transform: transform.FromField("ResponsiblePartyIDs").
+					Transform(transformCommaSeparatedList),
+			},
+			{
+				Name:        "status",
+				Type:        proto.ColumnType_STRING,
+				Description: "The status of the task.",
+				Transform:   transform.FromField("Status").NullIfZero(),
+			},
+			{
+				Name:        "completed",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the task is completed.",
+				Transform:   transform.FromField("Completed"),
+			},
+			{
+				Name:        "priority",
+				Type:        proto.ColumnType_STRING,
+				Description: "The priority of the task.",
+				Transform:   transform.FromField("Priority").NullIfZero(),
+			},
+			{
+				Name:        "progress",
+				Type:        proto.ColumnType_INT,
+				Description: "The percentage progress of the task.",
+				Transform:   transform.FromField("Progress"),
+			},
+			{
+				Name:        "start_date",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The start date of the task.",
+				Transform:   transform.FromField("StartDate").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "due_date",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The due date of the task.",
+				Transform:   transform.FromField("DueDate").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "estimated_minutes",
+				Type:        proto.ColumnType_INT,
+				Description: "The estimated time to complete the task, in minutes.",
+				Transform:   transform.FromField("EstimatedMinutes"),
+			},
+			{
+				Name:        "creator_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the person who created the task.",
+				Transform:   transform.FromField("CreatorID").NullIfZero(),
+			},
+			{
+				Name:        "company_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the company associated with the task.",
+				Transform:   transform.FromField("CompanyID").NullIfZero(),
+			},
+			{
+				Name:        "created_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the task was created.",
+				Transform:   transform.FromField("CreatedOn").NullIfZero(),
+			},
+			{
+				Name:        "last_changed_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date of the last change to the task.",
+				Transform:   transform.FromField("LastChangedOn").NullIfZero(),
+			},
+			{
+				Name:        "tags",
+				Type:        proto.ColumnType_JSON,
+				Description: "Tags associated with this task.",
+				Transform:   transform.FromField("Tags").NullIfZero(),
+			},
+		},
+	}
+}
+
+func listTeamworkTasks(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a list of tasks, scoped to a
+	// tasklist or project when one is given
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkTasks()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	path := "/tasks.json"
+	if tasklistID := d.EqualsQualString("tasklist_id"); tasklistID != "" {
+		path = fmt.Sprintf("/tasklists/%s/tasks.json", tasklistID)
+	} else if projectID := d.EqualsQualString("project_id"); projectID != "" {
+		path = fmt.Sprintf("/projects/%s/tasks.json", projectID)
+	}
+	// The tasks endpoints leave out completed tasks and subtasks unless asked for
+	// them
+	params := url.Values{
+		"includeCompletedTasks":    {"true"},
+		"includeCompletedSubtasks": {"true"},
+	}
+	endpoint := client.url(path) + "?" + limitPageSize(d, params).Encode()
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkTasks(): url: %s", endpoint))
+
+	err = StreamTeamworkItems(ctx, client, endpoint, plugin.Logger(ctx), func(page *TasksResponse) bool {
+		for _, t := range page.Tasks {
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkTasks()")
+	return nil, nil
+}
+
+type Task struct {
+	Completed           bool      `json:"completed"`
+	CompanyID           int       `json:"company-id"`
+	Content             string    `json:"content"`
+	CreatedOn           time.Time `json:"created-on"`
+	CreatorID           int       `json:"creator-id"`
+	Description         string    `json:"description"`
+	DueDate             string    `json:"due-date"`
+	EstimatedMinutes    int       `json:"estimated-minutes"`
+	ID                  int       `json:"id"`
+	LastChangedOn       time.Time `json:"last-changed-on"`
+	ParentTaskID        string    `json:"parentTaskId"`
+	Priority            string    `json:"priority"`
+	Progress            int       `json:"progress"`
+	ProjectID           int       `json:"project-id"`
+	ProjectName         string    `json:"project-name"`
+	ResponsiblePartyIDs string    `json:"responsible-party-ids"`
+	StartDate           string    `json:"start-date"`
+	Status              string    `json:"status"`
+	TasklistID          int       `json:"todo-list-id"`
+	TasklistName        string    `json:"todo-list-name"`
+	Tags                []Tag     `json:"tags"`
+}
+
+type Tag struct {
+	Color string      `json:"color"`
+	ID    json.Number `json:"id"`
+	Name  string      `json:"name"`
+}
+
+type TasksResponse struct {
+	Status string `json:"STATUS"`
+	Tasks  []Task `json:"todo-items"`
+}
@@ -0,0 +1,166 @@
+package teamwork
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableTeamworkTasklist(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_tasklist",
+		Description: "Tasklists from Teamwork.com",
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkTasklists,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "project_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the tasklist.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the tasklist.",
+				Transform:   transform.FromField("Name").NullIfZero(),
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the tasklist.",
+				Transform:   transform.FromField("Description").NullIfZero(),
+			},
+			{
+				Name:        "project_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the project the tasklist belongs to.",
+				Transform:   transform.FromField("ProjectID").NullIfZero(),
+			},
+			{
+				Name:        "project_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the project the tasklist belongs to.",
+				Transform:   transform.FromField("ProjectName").NullIfZero(),
+			},
+			{
+				Name:        "milestone_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the milestone the tasklist is attached to.",
+				Transform:   transform.FromField("MilestoneID").NullIfZero(),
+			},
+			{
+				Name:        "completed",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether all tasks in the tasklist are completed.",
+				Transform:   transform.FromField("Complete"),
+			},
+			{
+				Name:        "position",
+				Type:        proto.ColumnType_INT,
+				Description: "The position of the tasklist within its project.",
+				Transform:   transform.FromField("Position"),
+			},
+			{
+				Name:        "private",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the tasklist is private.",
+				Transform:   transform.FromField("Private"),
+			},
+			{
+				Name:        "pinned",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the tasklist is pinned.",
+				Transform:   transform.FromField("Pinned"),
+			},
+			{
+				Name:        "uncompleted_count",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of uncompleted tasks in the tasklist.",
+				Transform:   transform.FromField("UncompletedCount"),
+			},
+			{
+				Name:        "completed_count",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of completed tasks in the tasklist.",
+				Transform:   transform.FromField("CompletedCount"),
+			},
+		},
+	}
+}
+
+func listTeamworkTasklists(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a list of tasklists, scoped to a
+	// project when one is given
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkTasklists()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	path := "/tasklists.json"
+	if projectID := d.EqualsQualString("project_id"); projectID != "" {
+		path = fmt.Sprintf("/projects/%s/tasklists.json", projectID)
+	}
+	// The tasklists endpoints only return active tasklists unless asked for all
+	params := url.Values{"status": {"all"}}
+	endpoint := client.url(path) + "?" + limitPageSize(d, params).Encode()
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkTasklists(): url: %s", endpoint))
+
+	err = StreamTeamworkItems(ctx, client, endpoint, plugin.Logger(ctx), func(page *TasklistsResponse) bool {
+		for _, t := range page.Tasklists {
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkTasklists()")
+	return nil, nil
+}
+
+type Tasklist struct {
+	Complete         bool   `json:"complete"`
+	CompletedCount   int    `json:"completed-count"`
+	Description      string `json:"description"`
+	ID               string `json:"id"`
+	MilestoneID      string `json:"milestone-id"`
+	Name             string `json:"name"`
+	Pinned           bool   `json:"pinned"`
+	Position         int    `json:"position"`
+	Private          bool   `json:"private"`
+	ProjectID        string `json:"projectId"`
+	ProjectName      string `json:"projectName"`
+	UncompletedCount int    `json:"uncompleted-count"`
+}
+
+type TasklistsResponse struct {
+	Status    string     `json:"STATUS"`
+	Tasklists []Tasklist `json:"tasklists"`
+}
@@ -0,0 +1,307 @@
+package teamwork
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableTeamworkTeam(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_team",
+		Description: "Teams from Teamwork.com",
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkTeams,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "project_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the team.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the team.",
+				Transform:   transform.FromField("Name").NullIfZero(),
+			},
+			{
+				Name:        "handle",
+				Type:        proto.ColumnType_STRING,
+				Description: "The handle used to mention the team.",
+				Transform:   transform.FromField("Handle").NullIfZero(),
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the team.",
+				Transform:   transform.FromField("Description").NullIfZero(),
+			},
+			{
+				Name:        "parent_team_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the team's parent team, if any.",
+				Transform:   transform.FromField("ParentTeam.ID").NullIfZero(),
+			},
+			{
+				Name:        "parent_team_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the team's parent team, if any.",
+				Transform:   transform.FromField("ParentTeam.Name").NullIfZero(),
+			},
+			{
+				Name:        "company_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the company the team belongs to, if any.",
+				Transform:   transform.FromField("Company.ID").NullIfZero(),
+			},
+			{
+				Name:        "company_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the company the team belongs to, if any.",
+				Transform:   transform.FromField("Company.Name").NullIfZero(),
+			},
+			{
+				Name:        "project_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the project the team is scoped to, if any.",
+				Transform:   transform.FromQual("project_id").Transform(teamProjectID).NullIfZero(),
+			},
+			{
+				Name:        "project_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the project the team is scoped to, if any.",
+				Transform:   transform.FromField("Project.Name").NullIfZero(),
+			},
+		},
+	}
+}
+
+func tableTeamworkTeamMember(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_team_member",
+		Description: "Team memberships from Teamwork.com, one row per team and person",
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkTeamMembers,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "team_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "team_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the team.",
+				Transform:   transform.FromField("TeamID").NullIfZero(),
+			},
+			{
+				Name:        "team_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the team.",
+				Transform:   transform.FromField("TeamName").NullIfZero(),
+			},
+			{
+				Name:        "person_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the person who is a member of the team.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "first_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The first name of the team member.",
+				Transform:   transform.FromField("FirstName").NullIfZero(),
+			},
+			{
+				Name:        "last_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The last name of the team member.",
+				Transform:   transform.FromField("LastName").NullIfZero(),
+			},
+			{
+				Name:        "email",
+				Type:        proto.ColumnType_STRING,
+				Description: "The email address of the team member.",
+				Transform:   transform.FromField("EmailAddress").NullIfZero(),
+			},
+		},
+	}
+}
+
+func listTeamworkTeams(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a list of teams, scoped to a
+	// project when one is given
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkTeams()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	path := "/teams.json"
+	if projectID := d.EqualsQualString("project_id"); projectID != "" {
+		path = fmt.Sprintf("/projects/%s/teams.json", projectID)
+	}
+	url := client.url(path)
+	if params := limitPageSize(d, nil); len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkTeams(): url: %s", url))
+
+	err = StreamTeamworkItems(ctx, client, url, plugin.Logger(ctx), func(page *TeamsResponse) bool {
+		for _, t := range page.Teams {
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkTeams()")
+	return nil, nil
+}
+
+func listTeamworkTeamMembers(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and flatten the members of every team,
+	// or of a single team when one is given
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkTeamMembers()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	// streamMembers streams the members of a team, reporting whether more rows are wanted
+	streamMembers := func(team Team) bool {
+		for _, t := range team.Members {
+			t.TeamID = team.ID
+			t.TeamName = team.Name
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	if teamID := d.EqualsQualString("team_id"); teamID != "" {
+		var team TeamResponse
+
+		url := client.url(fmt.Sprintf("/teams/%s.json", teamID))
+
+		plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkTeamMembers(): url: %s", url))
+
+		_, err = ListTeamworkItems(ctx, client, url, &team, plugin.Logger(ctx))
+		if err != nil {
+			plugin.Logger(ctx).Error(err.Error())
+			return nil, err
+		}
+		streamMembers(team.Team)
+	} else {
+		url := client.url("/teams.json")
+
+		plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkTeamMembers(): url: %s", url))
+
+		err = StreamTeamworkItems(ctx, client, url, plugin.Logger(ctx), func(page *TeamsResponse) bool {
+			for _, team := range page.Teams {
+				if !streamMembers(team) {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			plugin.Logger(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkTeamMembers()")
+	return nil, nil
+}
+
+// teamProjectID returns the project the query was scoped to, falling back to
+// the project reported by the API for project teams.
+func teamProjectID(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	if d.Value != nil {
+		return d.Value, nil
+	}
+	team, ok := d.HydrateItem.(Team)
+	if !ok {
+		return nil, nil
+	}
+	return team.Project.ID, nil
+}
+
+type Team struct {
+	Description string       `json:"description"`
+	Handle      string       `json:"handle"`
+	ID          string       `json:"id"`
+	Members     []TeamMember `json:"members"`
+	Name        string       `json:"name"`
+	Company     struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"company"`
+	ParentTeam struct {
+		Handle string `json:"handle"`
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+	} `json:"parentTeam"`
+	Project struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"project"`
+}
+
+type TeamMember struct {
+	EmailAddress string `json:"emailAddress"`
+	FirstName    string `json:"firstName"`
+	ID           string `json:"id"`
+	LastName     string `json:"lastName"`
+	TeamID       string `json:"-"`
+	TeamName     string `json:"-"`
+}
+
+type TeamsResponse struct {
+	Status string `json:"STATUS"`
+	Teams  []Team `json:"teams"`
+}
+
+type TeamResponse struct {
+	Status string `json:"STATUS"`
+	Team   Team   `json:"team"`
+}
@@ -0,0 +1,302 @@
+package teamwork
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func tableTeamworkTimeEntry(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "teamwork_time_entry",
+		Description: "Time entries logged in Teamwork.com",
+		List: &plugin.ListConfig{
+			Hydrate: listTeamworkTimeEntries,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:       "project_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "person_id",
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "date",
+					Operators:  []string{">", ">=", "=", "<", "<="},
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+				{
+					Name:       "billable",
+					Operators:  []string{"=", "<>"},
+					Require:    plugin.Optional,
+					CacheMatch: "exact",
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the time entry.",
+				Transform:   transform.FromField("ID").NullIfZero(),
+			},
+			{
+				Name:        "person_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the person who logged the time.",
+				Transform:   transform.FromField("PersonID").NullIfZero(),
+			},
+			{
+				Name:        "person_first_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The first name of the person who logged the time.",
+				Transform:   transform.FromField("PersonFirstName").NullIfZero(),
+			},
+			{
+				Name:        "person_last_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The last name of the person who logged the time.",
+				Transform:   transform.FromField("PersonLastName").NullIfZero(),
+			},
+			{
+				Name:        "project_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the project the time was logged against.",
+				Transform:   transform.FromField("ProjectID").NullIfZero(),
+			},
+			{
+				Name:        "project_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the project the time was logged against.",
+				Transform:   transform.FromField("ProjectName").NullIfZero(),
+			},
+			{
+				Name:        "task_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the task the time was logged against, if any.",
+				Transform:   transform.FromField("TaskID").NullIfZero(),
+			},
+			{
+				Name:        "task_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the task the time was logged against, if any.",
+				Transform:   transform.FromField("TaskName").NullIfZero(),
+			},
+			{
+				Name:        "date",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date and start time of the time entry.",
+				Transform:   transform.FromField("Date").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "minutes",
+				Type:        proto.ColumnType_INT,
+				Description: "The total duration of the time entry, in minutes.",
+				Transform:   transform.From(timeEntryMinutes),
+			},
+			{
+				Name:        "hours",
+				Type:        proto.ColumnType_DOUBLE,
+				Description: "The total duration of the time entry, in hours.",
+				Transform:   transform.From(timeEntryHours),
+			},
+			{
+				Name:        "billable",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the time entry is billable.",
+				Transform:   transform.FromField("IsBillable"),
+			},
+			{
+				Name:        "invoice_number",
+				Type:        proto.ColumnType_STRING,
+				Description: "The number of the invoice the time entry was billed on, if any.",
+				Transform:   transform.FromField("InvoiceNo").NullIfZero(),
+			},
+			{
+				Name:        "invoiced",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Indicates whether the time entry has been invoiced.",
+				Transform:   transform.From(timeEntryInvoiced),
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the time entry.",
+				Transform:   transform.FromField("Description").NullIfZero(),
+			},
+			{
+				Name:        "company_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the company associated with the time entry.",
+				Transform:   transform.FromField("CompanyID").NullIfZero(),
+			},
+			{
+				Name:        "created_on",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The date the time entry was created.",
+				Transform:   transform.FromField("CreatedAt").Transform(transformTeamworkDate),
+			},
+			{
+				Name:        "tags",
+				Type:        proto.ColumnType_JSON,
+				Description: "Tags associated with this time entry.",
+				Transform:   transform.FromField("Tags").NullIfZero(),
+			},
+		},
+	}
+}
+
+func listTeamworkTimeEntries(
+	ctx context.Context,
+	d *plugin.QueryData,
+	_ *plugin.HydrateData,
+) (interface{}, error) {
+	// Logic to connect to Teamwork API and get a list of time entries, pushing
+	// project, person, date and billable qualifiers down to the API
+
+	plugin.Logger(ctx).Trace("Entering listTeamworkTimeEntries()")
+
+	client, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	path := "/time_entries.json"
+	if projectID := d.EqualsQualString("project_id"); projectID != "" {
+		path = fmt.Sprintf("/projects/%s/time_entries.json", projectID)
+	}
+	endpoint := client.url(path)
+	if params := limitPageSize(d, timeEntryParams(d)); len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	plugin.Logger(ctx).Trace(fmt.Sprintf("listTeamworkTimeEntries(): url: %s", endpoint))
+
+	err = StreamTeamworkItems(ctx, client, endpoint, plugin.Logger(ctx), func(page *TimeEntriesResponse) bool {
+		for _, t := range page.TimeEntries {
+			d.StreamListItem(ctx, t)
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	plugin.Logger(ctx).Trace("Exiting listTeamworkTimeEntries()")
+	return nil, nil
+}
+
+// timeEntryParams maps the person, date and billable qualifiers onto the
+// time entries endpoint's query parameters. Date bounds are inclusive, so
+// strict comparisons fetch a superset which Steampipe then filters.
+func timeEntryParams(d *plugin.QueryData) url.Values {
+	params := url.Values{}
+
+	if personID := d.EqualsQualString("person_id"); personID != "" {
+		params.Set("userId", personID)
+	}
+
+	if quals, ok := d.Quals["date"]; ok {
+		for _, q := range quals.Quals {
+			date := q.Value.GetTimestampValue().AsTime().Format("20060102")
+			switch q.Operator {
+			case ">", ">=":
+				params.Set("fromdate", date)
+			case "<", "<=":
+				params.Set("todate", date)
+			case "=":
+				params.Set("fromdate", date)
+				params.Set("todate", date)
+			}
+		}
+	}
+
+	if quals, ok := d.Quals["billable"]; ok {
+		for _, q := range quals.Quals {
+			billable := q.Value.GetBoolValue()
+			if q.Operator == "<>" {
+				billable = !billable
+			}
+			if billable {
+				params.Set("billableType", "billable")
+			} else {
+				params.Set("billableType", "non-billable")
+			}
+		}
+	}
+
+	return params
+}
+
+// timeEntryMinutes returns the total duration of a time entry in minutes.
+func timeEntryMinutes(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	timeEntry, ok := d.HydrateItem.(TimeEntry)
+	if !ok {
+		return nil, nil
+	}
+	return timeEntry.totalMinutes(), nil
+}
+
+// timeEntryHours returns the total duration of a time entry in hours.
+func timeEntryHours(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	timeEntry, ok := d.HydrateItem.(TimeEntry)
+	if !ok {
+		return nil, nil
+	}
+	return float64(timeEntry.totalMinutes()) / 60, nil
+}
+
+// timeEntryInvoiced reports whether a time entry has been billed on an invoice.
+func timeEntryInvoiced(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	timeEntry, ok := d.HydrateItem.(TimeEntry)
+	if !ok {
+		return nil, nil
+	}
+	return timeEntry.InvoiceNo != "", nil
+}
+
+type TimeEntry struct {
+	CompanyID       string `json:"company-id"`
+	CreatedAt       string `json:"createdAt"`
+	Date            string `json:"date"`
+	Description     string `json:"description"`
+	Hours           string `json:"hours"`
+	ID              string `json:"id"`
+	InvoiceNo       string `json:"invoiceNo"`
+	IsBillable      string `json:"isbillable"`
+	Minutes         string `json:"minutes"`
+	PersonFirstName string `json:"person-first-name"`
+	PersonID        string `json:"person-id"`
+	PersonLastName  string `json:"person-last-name"`
+	ProjectID       string `json:"project-id"`
+	ProjectName     string `json:"project-name"`
+	Tags            []Tag  `json:"tags"`
+	TaskID          string `json:"todo-item-id"`
+	TaskName        string `json:"todo-item-name"`
+}
+
+// totalMinutes combines the separate hours and minutes fields returned by the API.
+func (t TimeEntry) totalMinutes() int {
+	hours, _ := strconv.Atoi(t.Hours)
+	minutes, _ := strconv.Atoi(t.Minutes)
+	return hours*60 + minutes
+}
+
+type TimeEntriesResponse struct {
+	Status      string      `json:"STATUS"`
+	TimeEntries []TimeEntry `json:"time-entries"`
+}
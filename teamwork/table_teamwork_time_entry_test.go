@@ -0,0 +1,77 @@
+package teamwork
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+)
+
+func TestTimeEntryParams(t *testing.T) {
+	for _, test := range []struct {
+		Name  string
+		quals map[string][]*quals.Qual
+		want  url.Values
+	}{
+		{"none", nil, url.Values{}},
+		{
+			"person",
+			map[string][]*quals.Qual{"person_id": {{Column: "person_id", Operator: "=", Value: qualString("238")}}},
+			url.Values{"userId": {"238"}},
+		},
+		{
+			"after",
+			map[string][]*quals.Qual{"date": {{Column: "date", Operator: ">", Value: qualTimestamp("2024-01-02T03:04:05Z")}}},
+			url.Values{"fromdate": {"20240102"}},
+		},
+		{
+			"on or after",
+			map[string][]*quals.Qual{"date": {{Column: "date", Operator: ">=", Value: qualTimestamp("2024-01-02T00:00:00Z")}}},
+			url.Values{"fromdate": {"20240102"}},
+		},
+		{
+			"before",
+			map[string][]*quals.Qual{"date": {{Column: "date", Operator: "<", Value: qualTimestamp("2024-02-03T00:00:00Z")}}},
+			url.Values{"todate": {"20240203"}},
+		},
+		{
+			"on or before",
+			map[string][]*quals.Qual{"date": {{Column: "date", Operator: "<=", Value: qualTimestamp("2024-02-03T00:00:00Z")}}},
+			url.Values{"todate": {"20240203"}},
+		},
+		{
+			"on",
+			map[string][]*quals.Qual{"date": {{Column: "date", Operator: "=", Value: qualTimestamp("2024-03-04T00:00:00Z")}}},
+			url.Values{"fromdate": {"20240304"}, "todate": {"20240304"}},
+		},
+		{
+			"between",
+			map[string][]*quals.Qual{"date": {
+				{Column: "date", Operator: ">=", Value: qualTimestamp("2024-01-01T00:00:00Z")},
+				{Column: "date", Operator: "<", Value: qualTimestamp("2024-02-01T00:00:00Z")},
+			}},
+			url.Values{"fromdate": {"20240101"}, "todate": {"20240201"}},
+		},
+		{
+			"billable",
+			map[string][]*quals.Qual{"billable": {{Column: "billable", Operator: "=", Value: qualBool(true)}}},
+			url.Values{"billableType": {"billable"}},
+		},
+		{
+			"not billable",
+			map[string][]*quals.Qual{"billable": {{Column: "billable", Operator: "=", Value: qualBool(false)}}},
+			url.Values{"billableType": {"non-billable"}},
+		},
+		{
+			"billable not true",
+			map[string][]*quals.Qual{"billable": {{Column: "billable", Operator: "<>", Value: qualBool(true)}}},
+			url.Values{"billableType": {"non-billable"}},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := timeEntryParams(queryData(test.quals)); got.Encode() != test.want.Encode() {
+				t.Errorf("unexpected params: got %v, want %v", got.Encode(), test.want.Encode())
+			}
+		})
+	}
+}
@@ -1,25 +1,124 @@
 package teamwork
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 )
 
-// fetchPage is a helper function to fetch data from the API.
-func fetchPage(apiKey, url string, page int) (*http.Response, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s?page=%d", url, page), nil)
+// fetchPage is a helper function to fetch data from the API. Any query parameters
+// already present on the endpoint are preserved alongside the page number. Throttled
+// and transiently failing requests are retried with backoff, up to the client's
+// maxRetries, after which the last response is returned. The request, and any wait
+// before a retry, end early when ctx is cancelled.
+func fetchPage(ctx context.Context, client *teamworkClient, endpoint string, page int) (*http.Response, error) {
+	pageURL, err := withQuery(endpoint, url.Values{"page": {strconv.Itoa(page)}})
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("Authorization", "Bearer "+apiKey)
-	return client.Do(req)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := doRequest(ctx, client, pageURL)
+		if err != nil || !shouldRetryStatus(resp.StatusCode) || attempt >= client.maxRetries {
+			return resp, err
+		}
+		delay := retryDelay(resp, attempt, client.minRetryDelay)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// withQuery adds params to the query string of endpoint, replacing any values the
+// endpoint already has for the same parameters.
+func withQuery(endpoint string, params url.Values) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// doRequest sends an authenticated GET request. If an OAuth2 access token is
+// rejected, it is refreshed and the request is retried once.
+func doRequest(ctx context.Context, client *teamworkClient, endpoint string) (*http.Response, error) {
+	req, token, err := newRequest(ctx, client, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized ||
+		client.tokenSource == nil || !client.tokenSource.canRefresh() {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	if _, err := client.tokenSource.refresh(ctx, token); err != nil {
+		return nil, err
+	}
+	if req, _, err = newRequest(ctx, client, endpoint); err != nil {
+		return nil, err
+	}
+	return client.send(req)
+}
+
+// newRequest builds a GET request authenticated according to the client's auth
+// method, returning the credential it used so that a rejected OAuth2 access token
+// can be refreshed.
+func newRequest(ctx context.Context, client *teamworkClient, url string) (*http.Request, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch client.authMethod {
+	case authMethodBasic:
+		// The v1 API documents the API key as the username with any password
+		req.SetBasicAuth(client.apiKey, "x")
+		return req, client.apiKey, nil
+	case authMethodOAuth2:
+		token, err := client.tokenSource.token(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		req.Header.Add("Authorization", "Bearer "+token)
+		return req, token, nil
+	default:
+		req.Header.Add("Authorization", "Bearer "+client.apiKey)
+		return req, client.apiKey, nil
+	}
+}
+
+// readResponse reads the body of a response, returning an *APIError if the response
+// has an error status code.
+func readResponse(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newAPIError(resp, body)
+	}
+	return body, nil
 }
 
 // unmarshalResponse unmarshals the http response body into the given struct pointer.
@@ -55,7 +154,13 @@ func setPageData(apiResponse, responseData reflect.Value, page int) error {
 }
 
 // ListTeamworkItems fetches items from teamwork API and populates them into the given response struct.
-func ListTeamworkItems[T any](apiKey, url string, response *T, logger hclog.Logger) (*T, error) {
+func ListTeamworkItems[T any](
+	ctx context.Context,
+	client *teamworkClient,
+	url string,
+	response *T,
+	logger hclog.Logger,
+) (*T, error) {
 	logger.Trace("Entering ListTeamworkItems()")
 	defer logger.Trace("Exiting ListTeamworkItems()")
 
@@ -65,32 +170,143 @@ func ListTeamworkItems[T any](apiKey, url string, response *T, logger hclog.Logg
 	}
 
 	responseData := reflect.ValueOf(response).Elem()
-	page, totalPages := 1, 1
+	page := 0
+	var pageErr error
 
-	for page <= totalPages {
-		resp, err := fetchPage(apiKey, url, page)
-		if err != nil {
-			logger.Error(fmt.Sprintf("Error fetching page %d: %s", page, err))
-			return nil, err
-		}
-		defer resp.Body.Close()
+	err := StreamTeamworkItems(ctx, client, url, logger, func(apiResponse *T) bool {
+		page++
+		pageErr = setPageData(reflect.ValueOf(*apiResponse), responseData, page)
+		return pageErr == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if pageErr != nil {
+		return nil, pageErr
+	}
+	return response, nil
+}
 
-		var apiResponse T
-		if err := unmarshalResponse(resp, &apiResponse); err != nil {
-			logger.Error(fmt.Sprintf("Error unmarshalling response: %s", err))
-			return nil, err
-		}
+// StreamTeamworkItems fetches the pages of a Teamwork API v1 endpoint, passing each
+// to fn in order as soon as it and the pages before it have arrived. Once the first
+// page reports the number of pages, the rest are fetched up to the client's
+// concurrency at a time. Pagination stops early when fn returns false, so a query
+// that needs only a few rows does not download every page.
+func StreamTeamworkItems[T any](
+	ctx context.Context,
+	client *teamworkClient,
+	url string,
+	logger hclog.Logger,
+	fn func(page *T) bool,
+) error {
+	logger.Trace("Entering StreamTeamworkItems()")
+	defer logger.Trace("Exiting StreamTeamworkItems()")
 
-		if err := setPageData(reflect.ValueOf(apiResponse), responseData, page); err != nil {
-			return nil, err
-		}
+	first, header, err := fetchItemsPage[T](ctx, client, url, 1, logger)
+	if err != nil {
+		return err
+	}
+	totalPages := 1
+	if xPages := header.Get("x-pages"); xPages != "" {
+		totalPages, _ = strconv.Atoi(xPages)
+	}
+	if !fn(first) {
+		logger.Debug(fmt.Sprintf("Stopped after page 1 of %d", totalPages))
+		return nil
+	}
+	if totalPages <= 1 {
+		return nil
+	}
+
+	// Returning cancels the fetches still in flight, whether fn stopped early or a
+	// page failed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Each page holds a slot from when its fetch starts until fn has consumed it, so
+	// no more than concurrency pages are in flight or waiting at once
+	concurrency := client.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+
+	type pageResult struct {
+		items *T
+		err   error
+	}
+	results := make([]chan pageResult, totalPages+1)
+	for page := 2; page <= totalPages; page++ {
+		results[page] = make(chan pageResult, 1)
+	}
 
-		if page == 1 { // Only read total pages once
-			if xPages := resp.Header.Get("x-pages"); xPages != "" {
-				totalPages, _ = strconv.Atoi(xPages)
+	go func() {
+		for page := 2; page <= totalPages; page++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
 			}
+			go func(page int) {
+				items, _, err := fetchItemsPage[T](ctx, client, url, page, logger)
+				results[page] <- pageResult{items, err}
+			}(page)
+		}
+	}()
+
+	for page := 2; page <= totalPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var result pageResult
+		select {
+		case result = <-results[page]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		<-slots
+		if result.err != nil {
+			return result.err
+		}
+		if !fn(result.items) {
+			logger.Debug(fmt.Sprintf("Stopped after page %d of %d", page, totalPages))
+			return nil
 		}
-		page++
 	}
-	return response, nil
+	return nil
+}
+
+// fetchItemsPage fetches and decodes one page of a Teamwork API v1 endpoint,
+// returning it with the response headers.
+func fetchItemsPage[T any](
+	ctx context.Context,
+	client *teamworkClient,
+	url string,
+	page int,
+	logger hclog.Logger,
+) (*T, http.Header, error) {
+	resp, err := fetchPage(ctx, client, url, page)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error fetching page %d: %s", page, err))
+		return nil, nil, err
+	}
+	body, err := readResponse(resp)
+	resp.Body.Close()
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Error response for page %d: %s", page, err))
+		return nil, nil, err
+	}
+
+	var apiResponse T
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		logger.Error(fmt.Sprintf("Error unmarshalling response: %s", err))
+		return nil, nil, err
+	}
+
+	// v1 endpoints report some failures in the STATUS field of a 200 response
+	if status := reflect.ValueOf(apiResponse).FieldByName("Status"); status.IsValid() &&
+		status.Kind() == reflect.String && status.String() != "" && status.String() != "OK" {
+		return nil, nil, newAPIError(resp, body)
+	}
+	return &apiResponse, resp.Header, nil
 }
@@ -1,34 +1,106 @@
 package teamwork
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 )
 
+// suiteRequests records the request URIs received by the setupSuite server.
+var suiteRequests struct {
+	sync.Mutex
+	uris []string
+}
+
+// resetSuiteRequests clears the requests recorded by the setupSuite server.
+func resetSuiteRequests() {
+	suiteRequests.Lock()
+	defer suiteRequests.Unlock()
+	suiteRequests.uris = nil
+}
+
+// suiteRequestURIs returns the requests recorded by the setupSuite server.
+func suiteRequestURIs() []string {
+	suiteRequests.Lock()
+	defer suiteRequests.Unlock()
+	return append([]string(nil), suiteRequests.uris...)
+}
+
 func setupSuite(tb testing.TB) (func(tb testing.TB), string) {
 	tb.Log("setupSuite")
 
 	// Create a test server that always returns the same response
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suiteRequests.Lock()
+		suiteRequests.uris = append(suiteRequests.uris, r.URL.RequestURI())
+		suiteRequests.Unlock()
+
+		// Exchange the refresh token for a new access token
+		if r.URL.Path == "/launchpad/v1/token.json" {
+			if r.Method != http.MethodPost || r.PostFormValue("grant_type") != "refresh_token" ||
+				r.PostFormValue("refresh_token") != "refreshToken" ||
+				r.PostFormValue("client_id") != "clientID" || r.PostFormValue("client_secret") != "clientSecret" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token": "freshToken", "refresh_token": "newRefreshToken", "expires_in": 3600}`))
+			return
+		}
+
+		// Reject expired OAuth2 access tokens
+		if r.Header.Get("Authorization") == "Bearer expiredToken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// Fail as Teamwork does, with an HTML page or a STATUS other than OK
+		switch r.URL.Path {
+		case "/forbidden.json":
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html><body>Forbidden</body></html>"))
+			return
+		case "/error_status.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"STATUS": "Error", "MESSAGE": "Invalid value for project status"}`))
+			return
+		}
+
 		var file string
-		projectPattern := regexp.MustCompile(`^/project/[0-9]+\.json$`)
+		projectPattern := regexp.MustCompile(`^/projects/([0-9]+)\.json$`)
 		projectsPattern := regexp.MustCompile(`^/projects(_paginated)?\.json$`)
+		projectsV3Pattern := regexp.MustCompile(`^/projects/api/v3/projects\.json$`)
 
 		switch cmd := r.URL.Path; {
 		// Return an unpaginated list of projects
 		case projectsPattern.MatchString(cmd):
 			file = `test_data/projects.json`
-		// Return a single project
+		// Return a single project, or 404 for any project other than the fixture
 		case projectPattern.MatchString(cmd):
+			if projectPattern.FindStringSubmatch(cmd)[1] != "483331" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
 			file = `test_data/project.json`
+		// Return a v3 list of projects, paginated with a meta block
+		case projectsV3Pattern.MatchString(cmd):
+			file = fmt.Sprintf("test_data/projects_v3_page%s.json", r.URL.Query().Get("page"))
 		default:
 			tb.Errorf("unexpected path: %v", r.URL.Path)
 		}
@@ -76,6 +148,12 @@ func setupSuite(tb testing.TB) (func(tb testing.TB), string) {
 		if r.URL.Path == "/projects_paginated.json" {
 			w.Header().Set("x-pages", "2")
 			w.Header().Set("x-records", "142")
+
+			// Serve the requested page size, from 142 records in all
+			if pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize")); err == nil && pageSize > 0 {
+				contents = pageOfProjects(tb, contents, pageSize)
+				w.Header().Set("x-pages", strconv.Itoa((142+pageSize-1)/pageSize))
+			}
 		} else {
 			w.Header().Set("x-pages", "1")
 			w.Header().Set("x-records", "71")
@@ -98,6 +176,29 @@ func setupSuite(tb testing.TB) (func(tb testing.TB), string) {
 	}, ts.URL
 }
 
+// pageOfProjects trims the projects of a projects fixture to pageSize.
+func pageOfProjects(tb testing.TB, contents []byte, pageSize int) []byte {
+	var body map[string]json.RawMessage
+	var projects []json.RawMessage
+	if err := json.Unmarshal(contents, &body); err != nil {
+		tb.Errorf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(body["projects"], &projects); err != nil {
+		tb.Errorf("unexpected error: %v", err)
+	}
+	if pageSize < len(projects) {
+		projects = projects[:pageSize]
+	}
+	body["projects"], _ = json.Marshal(projects)
+	contents, _ = json.Marshal(body)
+	return contents
+}
+
+// testClient returns a client that authenticates with a static API key.
+func testClient() *teamworkClient {
+	return &teamworkClient{apiKey: "apiKey", httpClient: &http.Client{}}
+}
+
 func setupTest(tb testing.TB) func(tb testing.TB) {
 	tb.Log("setupTest")
 
@@ -116,8 +217,17 @@ func TestListTeamworkItemsProjects(t *testing.T) {
 		fn   func(*testing.T, string)
 	}{
 		{"testListTeamworkItemsProject", testListTeamworkItemsProject},
+		{"testListTeamworkItemsProjectNotFound", testListTeamworkItemsProjectNotFound},
+		{"testListTeamworkItemsErrors", testListTeamworkItemsErrors},
 		{"testListTeamworkItemsProjectsUnpaginated", testListTeamworkItemsProjectsUnpaginated},
 		{"testListTeamworkItemsProjectsPaginated", testListTeamworkItemsProjectsPaginated},
+		{"testStreamTeamworkItemsProjects", testStreamTeamworkItemsProjects},
+		{"testStreamTeamworkItemsLimit", testStreamTeamworkItemsLimit},
+		{"testListTeamworkItemsV3Projects", testListTeamworkItemsV3Projects},
+		{"testStreamTeamworkItemsV3Projects", testStreamTeamworkItemsV3Projects},
+		{"testListTeamworkItemsOAuth2Refresh", testListTeamworkItemsOAuth2Refresh},
+		{"testListTeamworkItemsOAuth2ConcurrentRefresh", testListTeamworkItemsOAuth2ConcurrentRefresh},
+		{"testListTeamworkItemsRateLimit", testListTeamworkItemsRateLimit},
 	} {
 		teardownTest := setupTest(t)
 		defer teardownTest(t)
@@ -130,7 +240,14 @@ func TestListTeamworkItemsProjects(t *testing.T) {
 func testListTeamworkItemsProject(t *testing.T, url string) {
 	// Call the API
 	var response ProjectResponse
-	_, err := ListTeamworkItems("apiKey", url+"/project/483331.json", &response, hclog.Default())
+	config := teamworkConfig{BaseURL: &url}
+	_, err := ListTeamworkItems(
+		context.Background(),
+		testClient(),
+		teamworkURL(config, "/projects/483331.json"),
+		&response,
+		hclog.Default(),
+	)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -143,10 +260,39 @@ func testListTeamworkItemsProject(t *testing.T, url string) {
 	}
 }
 
+func testListTeamworkItemsProjectNotFound(t *testing.T, url string) {
+	// Call the API
+	var response ProjectResponse
+	_, err := ListTeamworkItems(context.Background(), testClient(), url+"/projects/1.json", &response, hclog.Default())
+	if !errors.Is(err, errNotFound) {
+		t.Errorf("unexpected error: got %v, want %v", err, errNotFound)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.URL != url+"/projects/1.json?page=1" {
+		t.Errorf("unexpected error: got %v, want an *APIError for %v", err, url+"/projects/1.json?page=1")
+	}
+}
+
+func testListTeamworkItemsErrors(t *testing.T, url string) {
+	// An HTML error page is reported by status code rather than as invalid JSON
+	var response ProjectsResponse
+	_, err := ListTeamworkItems(context.Background(), testClient(), url+"/forbidden.json", &response, hclog.Default())
+	if !errors.Is(err, errForbidden) {
+		t.Errorf("unexpected error: got %v, want %v", err, errForbidden)
+	}
+
+	// A STATUS other than OK is an error, carrying the Teamwork message
+	_, err = ListTeamworkItems(context.Background(), testClient(), url+"/error_status.json", &response, hclog.Default())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Invalid value for project status" {
+		t.Errorf("unexpected error: got %v, want an *APIError with the Teamwork message", err)
+	}
+}
+
 func testListTeamworkItemsProjectsUnpaginated(t *testing.T, url string) {
 	// Call the API
 	var response ProjectsResponse
-	_, err := ListTeamworkItems("apiKey", url+"/projects.json", &response, hclog.Default())
+	_, err := ListTeamworkItems(context.Background(), testClient(), url+"/projects.json", &response, hclog.Default())
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -163,7 +309,8 @@ func testListTeamworkItemsProjectsPaginated(t *testing.T, url string) {
 	// Call the API
 	var response ProjectsResponse
 	_, err := ListTeamworkItems(
-		"apiKey",
+		context.Background(),
+		testClient(),
 		url+"/projects_paginated.json",
 		&response,
 		hclog.Default(),
@@ -179,3 +326,617 @@ func testListTeamworkItemsProjectsPaginated(t *testing.T, url string) {
 		t.Errorf("unexpected number of projects: got %v, want %v", len(response.Projects), 142)
 	}
 }
+
+func testStreamTeamworkItemsProjects(t *testing.T, url string) {
+	// Each page is passed on as it arrives
+	var pages, projects int
+	err := StreamTeamworkItems(context.Background(), testClient(), url+"/projects_paginated.json", hclog.Default(),
+		func(page *ProjectsResponse) bool {
+			pages++
+			projects += len(page.Projects)
+			return true
+		})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if pages != 2 || projects != 142 {
+		t.Errorf("unexpected pages and projects: got %v and %v, want %v and %v", pages, projects, 2, 142)
+	}
+
+	// and pagination stops as soon as no more are wanted
+	pages = 0
+	err = StreamTeamworkItems(context.Background(), testClient(), url+"/projects_paginated.json", hclog.Default(),
+		func(page *ProjectsResponse) bool {
+			pages++
+			return false
+		})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if pages != 1 {
+		t.Errorf("unexpected number of pages: got %v, want %v", pages, 1)
+	}
+}
+
+func testStreamTeamworkItemsLimit(t *testing.T, url string) {
+	for _, test := range []struct {
+		Name         string
+		limit        int64
+		wantPageSize string
+		wantRequests int
+	}{
+		{"small limit", 5, "5", 1},
+		{"limit beyond the 71 projects of the first page", 100, "100", 2},
+		{"limit over the maximum page size", 1000, "500", 1},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			limit := test.limit
+			d := &plugin.QueryData{QueryContext: &plugin.QueryContext{Limit: &limit}}
+			endpoint, err := withQuery(url+"/projects_paginated.json", limitPageSize(d, nil))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Stream rows as a table does, stopping once the limit is reached
+			resetSuiteRequests()
+			rows := int64(0)
+			err = StreamTeamworkItems(context.Background(), testClient(), endpoint, hclog.Default(), func(page *ProjectsResponse) bool {
+				for range page.Projects {
+					if rows++; rows == limit {
+						return false
+					}
+				}
+				return true
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			requests := suiteRequestURIs()
+			if len(requests) != test.wantRequests {
+				t.Errorf("unexpected number of requests: got %v (%v), want %v", len(requests), requests, test.wantRequests)
+			}
+			for _, uri := range requests {
+				if !strings.Contains(uri, "pageSize="+test.wantPageSize) {
+					t.Errorf("unexpected request: got %v, want pageSize=%v", uri, test.wantPageSize)
+				}
+			}
+		})
+	}
+}
+
+func testListTeamworkItemsV3Projects(t *testing.T, url string) {
+	// Call the API
+	projects, included, err := ListTeamworkItemsV3[V3Project](
+		context.Background(),
+		testClient(),
+		url+"/projects/api/v3/projects.json?include="+v3ProjectIncludes,
+		"projects",
+		hclog.Default(),
+	)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if len(projects) != 3 {
+		t.Fatalf("unexpected number of projects: got %v, want %v", len(projects), 3)
+	}
+
+	project := projects[1].toProject(included)
+	if project.ID != "483332" {
+		t.Errorf("unexpected project ID: got %v, want %v", project.ID, "483332")
+	}
+	if project.Company.Name != "Cloudticity" {
+		t.Errorf("unexpected company name: got %v, want %v", project.Company.Name, "Cloudticity")
+	}
+	if project.Category.Name != "Marketing" {
+		t.Errorf("unexpected category name: got %v, want %v", project.Category.Name, "Marketing")
+	}
+	if project.StartDate != "2024-01-08T00:00:00Z" {
+		t.Errorf("unexpected start date: got %v, want %v", project.StartDate, "2024-01-08T00:00:00Z")
+	}
+	if len(project.Tags) != 1 {
+		t.Errorf("unexpected number of tags: got %v, want %v", len(project.Tags), 1)
+	}
+
+	// Resources side-loaded on later pages are merged with those from the first
+	project = projects[2].toProject(included)
+	if project.Company.Name != "Northwind Health" {
+		t.Errorf("unexpected company name: got %v, want %v", project.Company.Name, "Northwind Health")
+	}
+}
+
+func testStreamTeamworkItemsV3Projects(t *testing.T, url string) {
+	// Pagination stops as soon as no more pages are wanted
+	pages := 0
+	err := StreamTeamworkItemsV3(context.Background(), testClient(), url+"/projects/api/v3/projects.json", "projects", hclog.Default(),
+		func(projects []V3Project, included V3Included) bool {
+			pages++
+			if len(projects) != 2 {
+				t.Errorf("unexpected number of projects: got %v, want %v", len(projects), 2)
+			}
+			return false
+		})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if pages != 1 {
+		t.Errorf("unexpected number of pages: got %v, want %v", pages, 1)
+	}
+}
+
+func testListTeamworkItemsOAuth2Refresh(t *testing.T, url string) {
+	accessToken, refreshToken := "expiredToken", "refreshToken"
+	clientID, clientSecret := "clientID", "clientSecret"
+	tokenURL := url + "/launchpad/v1/token.json"
+	client := newTeamworkClient(teamworkConfig{
+		AccessToken:  &accessToken,
+		RefreshToken: &refreshToken,
+		ClientID:     &clientID,
+		ClientSecret: &clientSecret,
+		TokenURL:     &tokenURL,
+	})
+
+	// Call the API with an access token that the server rejects
+	var response ProjectResponse
+	_, err := ListTeamworkItems(context.Background(), client, url+"/projects/483331.json", &response, hclog.Default())
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if response.Project.ID != "483331" {
+		t.Errorf("unexpected project ID: got %v, want %v", response.Project.ID, "483331")
+	}
+	if client.tokenSource.accessToken != "freshToken" {
+		t.Errorf("unexpected access token: got %v, want %v", client.tokenSource.accessToken, "freshToken")
+	}
+	if client.tokenSource.refreshToken != "newRefreshToken" {
+		t.Errorf("unexpected refresh token: got %v, want %v", client.tokenSource.refreshToken, "newRefreshToken")
+	}
+
+	// An expired token is refreshed before the request is made
+	client.tokenSource.expiry = time.Now().Add(-time.Minute)
+	client.tokenSource.refreshToken = "refreshToken"
+	if token, err := client.tokenSource.token(context.Background()); err != nil || token != "freshToken" {
+		t.Errorf("unexpected token: got %v (%v), want %v", token, err, "freshToken")
+	}
+	if client.tokenSource.expiry.Before(time.Now()) {
+		t.Errorf("unexpected expiry: got %v, want a time in the future", client.tokenSource.expiry)
+	}
+}
+
+func testListTeamworkItemsOAuth2ConcurrentRefresh(t *testing.T, url string) {
+	accessToken, refreshToken := "expiredToken", "refreshToken"
+	clientID, clientSecret := "clientID", "clientSecret"
+	tokenURL := url + "/launchpad/v1/token.json"
+	client := newTeamworkClient(teamworkConfig{
+		AccessToken:  &accessToken,
+		RefreshToken: &refreshToken,
+		ClientID:     &clientID,
+		ClientSecret: &clientSecret,
+		TokenURL:     &tokenURL,
+	})
+
+	// Both requests are rejected, and only one refreshes the shared token
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var response ProjectResponse
+			_, errs[i] = ListTeamworkItems(
+				context.Background(), client, url+"/projects/483331.json", &response, hclog.Default())
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if token, err := client.tokenSource.token(context.Background()); err != nil || token != "freshToken" {
+		t.Errorf("unexpected token: got %v (%v), want %v", token, err, "freshToken")
+	}
+}
+
+func testListTeamworkItemsRateLimit(t *testing.T, url string) {
+	// The limiter adopts the limit reported by the API
+	client := testClient()
+	client.limiter = newRateLimiter(6000, false)
+	var response ProjectsResponse
+	if _, err := ListTeamworkItems(context.Background(), client, url+"/projects.json", &response, hclog.Default()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := client.limiter.requestsPerMinute(); got != 150 {
+		t.Errorf("unexpected requests per minute: got %v, want %v", got, 150)
+	}
+
+	// unless the rate is fixed by requests_per_minute
+	client.limiter = newRateLimiter(6000, true)
+	if _, err := ListTeamworkItems(context.Background(), client, url+"/projects.json", &response, hclog.Default()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := client.limiter.requestsPerMinute(); got != 6000 {
+		t.Errorf("unexpected requests per minute: got %v, want %v", got, 6000)
+	}
+}
+
+func TestRateLimiterThrottles(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"STATUS": "OK"}`))
+	}))
+	defer ts.Close()
+
+	// 600 requests a minute allows a burst of 10, then one request every 100ms
+	client := testClient()
+	client.limiter = newRateLimiter(600, true)
+
+	start := time.Now()
+	for page := 1; page <= 15; page++ {
+		resp, err := fetchPage(context.Background(), client, ts.URL+"/projects.json", page)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("unexpected duration: got %v, want at least %v", elapsed, 400*time.Millisecond)
+	}
+}
+
+func TestStreamTeamworkItemsConcurrency(t *testing.T) {
+	const pages, latency = 8, 50 * time.Millisecond
+
+	// Serve one project per page, slowly, tracking how many requests overlap
+	var mu sync.Mutex
+	var inFlight, maxInFlight, requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(latency)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		w.Header().Set("x-pages", strconv.Itoa(pages))
+		fmt.Fprintf(w, `{"STATUS": "OK", "projects": [{"id": "%s"}]}`, r.URL.Query().Get("page"))
+	}))
+	defer ts.Close()
+
+	client := testClient()
+	client.concurrency = 3
+
+	// Pages are fetched concurrently but passed on in order
+	var ids []string
+	start := time.Now()
+	err := StreamTeamworkItems(context.Background(), client, ts.URL+"/projects.json", hclog.Default(), func(page *ProjectsResponse) bool {
+		for _, project := range page.Projects {
+			ids = append(ids, project.ID)
+		}
+		return true
+	})
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"1", "2", "3", "4", "5", "6", "7", "8"}; fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("unexpected project order: got %v, want %v", ids, want)
+	}
+	if maxInFlight < 2 || maxInFlight > client.concurrency {
+		t.Errorf("unexpected concurrent requests: got %v, want between 2 and %v", maxInFlight, client.concurrency)
+	}
+	if elapsed >= pages*latency {
+		t.Errorf("unexpected duration: got %v, want less than %v", elapsed, pages*latency)
+	}
+
+	// Pagination stops once no more pages are wanted, with at most concurrency pages
+	// fetched beyond the last one used
+	mu.Lock()
+	requests = 0
+	mu.Unlock()
+	err = StreamTeamworkItems(context.Background(), client, ts.URL+"/projects.json", hclog.Default(), func(page *ProjectsResponse) bool {
+		return page.Projects[0].ID != "2"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Let any fetches already started finish
+	time.Sleep(2 * latency)
+	mu.Lock()
+	defer mu.Unlock()
+	if requests > 2+client.concurrency {
+		t.Errorf("unexpected number of requests: got %v, want at most %v", requests, 2+client.concurrency)
+	}
+}
+
+func TestStreamTeamworkItemsCancellation(t *testing.T) {
+	const pages = 10
+
+	var mu sync.Mutex
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+
+		w.Header().Set("x-pages", strconv.Itoa(pages))
+		fmt.Fprintf(w, `{"STATUS": "OK", "projects": [{"id": "%s"}]}`, r.URL.Query().Get("page"))
+	}))
+	defer ts.Close()
+
+	client := testClient()
+	client.concurrency = 1
+
+	// Cancel the query while the second page is being streamed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := StreamTeamworkItems(ctx, client, ts.URL+"/projects.json", hclog.Default(), func(page *ProjectsResponse) bool {
+		if page.Projects[0].ID == "2" {
+			cancel()
+		}
+		return true
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+
+	// No page is requested after the cancellation, beyond one already in flight
+	mu.Lock()
+	defer mu.Unlock()
+	if requests > 3 {
+		t.Errorf("unexpected number of requests: got %v, want at most %v", requests, 3)
+	}
+}
+
+func TestStreamTeamworkItemsStopCancelsFetches(t *testing.T) {
+	const pages = 10
+
+	var mu sync.Mutex
+	hanging, cancelled := 0, 0
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page != "1" && page != "2" {
+			// Hang until the client gives up on the request
+			mu.Lock()
+			hanging++
+			mu.Unlock()
+			select {
+			case <-r.Context().Done():
+				mu.Lock()
+				cancelled++
+				mu.Unlock()
+			case <-release:
+			}
+			return
+		}
+		w.Header().Set("x-pages", strconv.Itoa(pages))
+		fmt.Fprintf(w, `{"STATUS": "OK", "projects": [{"id": "%s"}]}`, page)
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	client := testClient()
+	client.concurrency = 3
+
+	// Stop after the second page, while later pages are still being fetched
+	err := StreamTeamworkItems(context.Background(), client, ts.URL+"/projects.json", hclog.Default(), func(page *ProjectsResponse) bool {
+		return page.Projects[0].ID != "2"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Every fetch left in flight is cancelled rather than waiting on the server
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		done := hanging == cancelled
+		mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			mu.Lock()
+			defer mu.Unlock()
+			t.Fatalf("unexpected in-flight fetches: %v of %v cancelled", cancelled, hanging)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFetchPageTimeout(t *testing.T) {
+	// Hang until the client gives up
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	client := testClient()
+	client.httpClient.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	if _, err := fetchPage(context.Background(), client, ts.URL+"/projects.json", 1); err == nil {
+		t.Errorf("unexpected error: got nil, want a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("unexpected duration: got %v, want about %v", elapsed, client.httpClient.Timeout)
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	kinds := []error{errUnauthorized, errForbidden, errNotFound, errRateLimited, errServerError}
+
+	for _, test := range []struct {
+		StatusCode int
+		want       error
+	}{
+		{http.StatusUnauthorized, errUnauthorized},
+		{http.StatusForbidden, errForbidden},
+		{http.StatusNotFound, errNotFound},
+		{http.StatusTooManyRequests, errRateLimited},
+		{http.StatusBadGateway, errServerError},
+		{http.StatusBadRequest, nil},
+	} {
+		t.Run(http.StatusText(test.StatusCode), func(t *testing.T) {
+			err := &APIError{StatusCode: test.StatusCode}
+			for _, kind := range kinds {
+				if got := errors.Is(err, kind); got != (kind == test.want) {
+					t.Errorf("unexpected errors.Is(%v): got %v, want %v", kind, got, !got)
+				}
+			}
+		})
+	}
+}
+
+func TestShouldRetryError(t *testing.T) {
+	zero := 0
+
+	for _, test := range []struct {
+		Name   string
+		err    error
+		config teamworkConfig
+		want   bool
+	}{
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, teamworkConfig{}, true},
+		{"service unavailable", &APIError{StatusCode: http.StatusServiceUnavailable}, teamworkConfig{}, true},
+		{"internal server error", &APIError{StatusCode: http.StatusInternalServerError}, teamworkConfig{}, false},
+		{"not implemented", &APIError{StatusCode: http.StatusNotImplemented}, teamworkConfig{}, false},
+		{"not found", &APIError{StatusCode: http.StatusNotFound}, teamworkConfig{}, false},
+		{"other error", errors.New("boom"), teamworkConfig{}, false},
+		{"retries disabled", &APIError{StatusCode: http.StatusTooManyRequests}, teamworkConfig{MaxRetries: &zero}, false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			d := &plugin.QueryData{Connection: &plugin.Connection{Config: test.config}}
+			if got := shouldRetryError(context.Background(), d, nil, test.err); got != test.want {
+				t.Errorf("unexpected retry: got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewRequestAuthMethod(t *testing.T) {
+	apiKey, accessToken := "apiKey", "accessToken"
+
+	for _, test := range []struct {
+		Name       string
+		authMethod string
+		want       string
+	}{
+		{"bearer", authMethodBearer, "Bearer apiKey"},
+		{"basic", authMethodBasic, "Basic " + base64.StdEncoding.EncodeToString([]byte("apiKey:x"))},
+		{"oauth2", authMethodOAuth2, "Bearer accessToken"},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			client := newTeamworkClient(teamworkConfig{
+				APIKey:      &apiKey,
+				AccessToken: &accessToken,
+				AuthMethod:  &test.authMethod,
+			})
+			req, _, err := newRequest(context.Background(), client, "https://teamwork.example.com/projects.json")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := req.Header.Get("Authorization"); got != test.want {
+				t.Errorf("unexpected Authorization header: got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFetchPageRetries(t *testing.T) {
+	for _, test := range []struct {
+		Name       string
+		statuses   []int
+		maxRetries int
+		wantStatus int
+		wantCalls  int
+	}{
+		{"throttled", []int{http.StatusTooManyRequests, http.StatusTooManyRequests}, 3, http.StatusOK, 3},
+		{"unavailable", []int{http.StatusServiceUnavailable, http.StatusBadGateway}, 3, http.StatusOK, 3},
+		{"exhausted", []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout}, 1, http.StatusGatewayTimeout, 2},
+		{"not retried", []int{http.StatusInternalServerError}, 3, http.StatusInternalServerError, 1},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			// Fail with each status in turn, then succeed
+			calls := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls <= len(test.statuses) {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(test.statuses[calls-1])
+					return
+				}
+				w.Write([]byte(`{"STATUS": "OK"}`))
+			}))
+			defer ts.Close()
+
+			client := testClient()
+			client.maxRetries = test.maxRetries
+			client.minRetryDelay = time.Millisecond
+
+			resp, err := fetchPage(context.Background(), client, ts.URL+"/projects.json", 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != test.wantStatus {
+				t.Errorf("unexpected status: got %v, want %v", resp.StatusCode, test.wantStatus)
+			}
+			if calls != test.wantCalls {
+				t.Errorf("unexpected number of requests: got %v, want %v", calls, test.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	response := func(status int, headers map[string]string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		for key, value := range headers {
+			resp.Header.Set(key, value)
+		}
+		return resp
+	}
+
+	// Delays requested by the API are honoured, up to maxRetryDelay
+	for _, test := range []struct {
+		Name string
+		resp *http.Response
+		want time.Duration
+	}{
+		{"Retry-After", response(http.StatusServiceUnavailable, map[string]string{"Retry-After": "5"}), 5 * time.Second},
+		{"x-ratelimit-reset", response(http.StatusTooManyRequests, map[string]string{"x-ratelimit-reset": "12"}), 12 * time.Second},
+		{"capped", response(http.StatusTooManyRequests, map[string]string{"Retry-After": "3600"}), maxRetryDelay},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := retryDelay(test.resp, 0, time.Second); got != test.want {
+				t.Errorf("unexpected delay: got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	// Otherwise the delay doubles with each attempt, with jitter
+	t.Run("backoff", func(t *testing.T) {
+		resp := response(http.StatusBadGateway, nil)
+		for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+			got := retryDelay(resp, attempt, time.Second)
+			if got < want/2 || got > want {
+				t.Errorf("unexpected delay for attempt %d: got %v, want between %v and %v", attempt, got, want/2, want)
+			}
+		}
+		if got := retryDelay(resp, 40, time.Second); got > maxRetryDelay {
+			t.Errorf("unexpected delay: got %v, want at most %v", got, maxRetryDelay)
+		}
+	})
+}
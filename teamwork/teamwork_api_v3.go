@@ -0,0 +1,165 @@
+package teamwork
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// v3PathPrefix is the path prefix of all Teamwork API v3 endpoints.
+const v3PathPrefix = "/projects/api/v3"
+
+// V3Meta is the metadata block returned by Teamwork API v3 list endpoints.
+type V3Meta struct {
+	Page struct {
+		PageOffset int  `json:"pageOffset"`
+		PageSize   int  `json:"pageSize"`
+		Count      int  `json:"count"`
+		HasMore    bool `json:"hasMore"`
+	} `json:"page"`
+}
+
+// V3Included holds the related resources side-loaded by a Teamwork API v3
+// response, keyed by resource type and then by ID.
+type V3Included map[string]map[string]json.RawMessage
+
+// V3Ref is a reference from a v3 resource to a related, possibly side-loaded, resource.
+type V3Ref struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+}
+
+// Decode unmarshals the included resource referenced by ref into target,
+// reporting whether the resource was present in the response.
+func (i V3Included) Decode(ref *V3Ref, target interface{}) (bool, error) {
+	if ref == nil {
+		return false, nil
+	}
+	raw, ok := i[ref.Type][fmt.Sprint(ref.ID)]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, target)
+}
+
+// merge adds the resources from other into i.
+func (i V3Included) merge(other V3Included) {
+	for kind, resources := range other {
+		if i[kind] == nil {
+			i[kind] = map[string]json.RawMessage{}
+		}
+		for id, raw := range resources {
+			i[kind][id] = raw
+		}
+	}
+}
+
+// ListTeamworkItemsV3 fetches items from a Teamwork API v3 endpoint, following the
+// meta.page block until no pages remain. The items are read from the given key of
+// each response, which may hold either a list or a single object, and the side-loaded
+// resources from every page are merged and returned alongside them.
+func ListTeamworkItemsV3[T any](
+	ctx context.Context,
+	client *teamworkClient,
+	url, key string,
+	logger hclog.Logger,
+) ([]T, V3Included, error) {
+	logger.Trace("Entering ListTeamworkItemsV3()")
+	defer logger.Trace("Exiting ListTeamworkItemsV3()")
+
+	var items []T
+	included := V3Included{}
+
+	err := StreamTeamworkItemsV3(ctx, client, url, key, logger, func(pageItems []T, pageIncluded V3Included) bool {
+		items = append(items, pageItems...)
+		included.merge(pageIncluded)
+		return true
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, included, nil
+}
+
+// StreamTeamworkItemsV3 fetches the pages of a Teamwork API v3 endpoint in turn,
+// passing the items and side-loaded resources of each to fn as soon as it arrives.
+// Pagination stops early when fn returns false.
+func StreamTeamworkItemsV3[T any](
+	ctx context.Context,
+	client *teamworkClient,
+	url, key string,
+	logger hclog.Logger,
+	fn func(items []T, included V3Included) bool,
+) error {
+	logger.Trace("Entering StreamTeamworkItemsV3()")
+	defer logger.Trace("Exiting StreamTeamworkItemsV3()")
+
+	for page, hasMore := 1, true; hasMore; page++ {
+		resp, err := fetchPage(ctx, client, url, page)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Error fetching page %d: %s", page, err))
+			return err
+		}
+		data, err := readResponse(resp)
+		resp.Body.Close()
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Error response for page %d: %s", page, err))
+			return err
+		}
+
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(data, &body); err != nil {
+			logger.Error(fmt.Sprintf("Error unmarshalling response: %s", err))
+			return err
+		}
+
+		items, err := decodeV3Items[T](body[key])
+		if err != nil {
+			logger.Error(fmt.Sprintf("Error unmarshalling %s: %s", key, err))
+			return err
+		}
+
+		included := V3Included{}
+		if raw, ok := body["included"]; ok {
+			if err := json.Unmarshal(raw, &included); err != nil {
+				logger.Error(fmt.Sprintf("Error unmarshalling included: %s", err))
+				return err
+			}
+		}
+
+		hasMore = false
+		if raw, ok := body["meta"]; ok {
+			var meta V3Meta
+			if err := json.Unmarshal(raw, &meta); err != nil {
+				logger.Error(fmt.Sprintf("Error unmarshalling meta: %s", err))
+				return err
+			}
+			hasMore = meta.Page.HasMore
+		}
+
+		if !fn(items, included) {
+			logger.Debug(fmt.Sprintf("Stopped after page %d", page))
+			return nil
+		}
+	}
+	return nil
+}
+
+// decodeV3Items unmarshals a v3 payload holding either a list of items or a single item.
+func decodeV3Items[T any](raw json.RawMessage) ([]T, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] == '[' {
+		var items []T
+		err := json.Unmarshal(raw, &items)
+		return items, err
+	}
+	var item T
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	return []T{item}, nil
+}
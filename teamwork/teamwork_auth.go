@@ -0,0 +1,128 @@
+package teamwork
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultTokenURL is the Teamwork endpoint used to refresh OAuth2 access tokens.
+const defaultTokenURL = "https://www.teamwork.com/launchpad/v1/token.json"
+
+// tokenExpiryMargin is how long before its expiry an access token is refreshed.
+const tokenExpiryMargin = time.Minute
+
+// oauth2TokenSource supplies the OAuth2 access token for a connection, refreshing it
+// with the refresh token when it expires or is rejected by the API. It is shared by
+// every query on the connection, so a refreshed token outlives the query that
+// refreshed it.
+type oauth2TokenSource struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	httpClient   *http.Client
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiry       time.Time
+}
+
+// tokenResponse is the body returned by the OAuth2 token endpoint.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// canRefresh reports whether the token source has what it needs to refresh the access token.
+func (s *oauth2TokenSource) canRefresh() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.canRefreshLocked()
+}
+
+// canRefreshLocked is canRefresh for callers that already hold s.mu.
+func (s *oauth2TokenSource) canRefreshLocked() bool {
+	return s.refreshToken != "" && s.clientID != "" && s.clientSecret != ""
+}
+
+// token returns a valid access token, refreshing it first if it is known to have expired.
+func (s *oauth2TokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && (s.expiry.IsZero() || time.Now().Add(tokenExpiryMargin).Before(s.expiry)) {
+		return s.accessToken, nil
+	}
+	if err := s.refreshLocked(ctx); err != nil {
+		return "", err
+	}
+	return s.accessToken, nil
+}
+
+// refresh exchanges the refresh token for a new access token after the API rejected
+// stale. If another request has already replaced stale, the current token is returned
+// without contacting the token endpoint again.
+func (s *oauth2TokenSource) refresh(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != stale {
+		return s.accessToken, nil
+	}
+	if err := s.refreshLocked(ctx); err != nil {
+		return "", err
+	}
+	return s.accessToken, nil
+}
+
+// refreshLocked calls the token endpoint with the refresh token grant. s.mu must be held.
+func (s *oauth2TokenSource) refreshLocked(ctx context.Context) error {
+	if !s.canRefreshLocked() {
+		return fmt.Errorf("OAuth2 access token expired and refresh_token, client_id and client_secret are not all set")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", s.refreshToken)
+	form.Set("client_id", s.clientID)
+	form.Set("client_secret", s.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("refreshing OAuth2 access token: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("refreshing OAuth2 access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("refreshing OAuth2 access token: %s returned %s", s.tokenURL, resp.Status)
+	}
+
+	var token tokenResponse
+	if err := unmarshalResponse(resp, &token); err != nil {
+		return fmt.Errorf("refreshing OAuth2 access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("refreshing OAuth2 access token: %s returned no access_token", s.tokenURL)
+	}
+
+	s.accessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+	s.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		s.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package teamwork
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// teamworkClient holds the per-connection state used to call the Teamwork API.
+type teamworkClient struct {
+	config        teamworkConfig
+	httpClient    *http.Client
+	authMethod    string
+	apiKey        string
+	tokenSource   *oauth2TokenSource
+	limiter       *rateLimiter
+	concurrency   int
+	maxRetries    int
+	minRetryDelay time.Duration
+}
+
+// clients holds the client of each connection, keyed by connection name.
+var (
+	clientsMu sync.Mutex
+	clients   = map[string]*teamworkClient{}
+)
+
+// newTeamworkClient creates a client for a validated connection config.
+func newTeamworkClient(config teamworkConfig) *teamworkClient {
+	client := &teamworkClient{
+		config:        config,
+		httpClient:    &http.Client{Timeout: requestTimeout(config)},
+		authMethod:    authMethod(config),
+		concurrency:   maxConcurrency(config),
+		maxRetries:    maxRetries(config),
+		minRetryDelay: minRetryDelay(config),
+	}
+	if config.RequestsPerMinute != nil {
+		client.limiter = newRateLimiter(*config.RequestsPerMinute, true)
+	} else {
+		client.limiter = newRateLimiter(defaultRequestsPerMinute, false)
+	}
+	if config.APIKey != nil {
+		client.apiKey = *config.APIKey
+	}
+
+	if client.authMethod == authMethodOAuth2 {
+		client.tokenSource = &oauth2TokenSource{
+			accessToken: *config.AccessToken,
+			tokenURL:    defaultTokenURL,
+			httpClient:  client.httpClient,
+		}
+		if config.RefreshToken != nil {
+			client.tokenSource.refreshToken = *config.RefreshToken
+		}
+		if config.ClientID != nil {
+			client.tokenSource.clientID = *config.ClientID
+		}
+		if config.ClientSecret != nil {
+			client.tokenSource.clientSecret = *config.ClientSecret
+		}
+		if config.TokenURL != nil && *config.TokenURL != "" {
+			client.tokenSource.tokenURL = *config.TokenURL
+		}
+	}
+
+	return client
+}
+
+// connect returns the client for a query's connection. The connection config is
+// validated and the client created the first time the connection is used, and again
+// whenever the connection config changes.
+func connect(ctx context.Context, d *plugin.QueryData) (*teamworkClient, error) {
+	config, err := validConfig(d)
+	if err != nil {
+		return nil, err
+	}
+
+	name := ""
+	if d.Connection != nil {
+		name = d.Connection.Name
+	}
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	if client, ok := clients[name]; ok && reflect.DeepEqual(client.config, config) {
+		return client, nil
+	}
+
+	plugin.Logger(ctx).Debug("connect(): creating client", "connection", name)
+	client := newTeamworkClient(config)
+	clients[name] = client
+	return client, nil
+}
+
+// url builds the URL of a Teamwork API endpoint for the client's connection.
+func (c *teamworkClient) url(path string) string {
+	return teamworkURL(c.config, path)
+}
+
+// send waits for the connection's rate limiter, if any, then sends req and adjusts
+// the limiter to the rate limit reported in the response.
+func (c *teamworkClient) send(req *http.Request) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err == nil && c.limiter != nil {
+		c.limiter.observe(resp)
+	}
+	return resp, err
+}
@@ -0,0 +1,111 @@
+package teamwork
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// Kinds of Teamwork API error. An *APIError matches the kind for its status code
+// with errors.Is.
+var (
+	errUnauthorized = errors.New("teamwork: unauthorized")
+	errForbidden    = errors.New("teamwork: forbidden")
+	errNotFound     = errors.New("teamwork: resource not found")
+	errRateLimited  = errors.New("teamwork: rate limited")
+	errServerError  = errors.New("teamwork: server error")
+)
+
+// APIError is returned when the Teamwork API responds with an error status code, or
+// with a STATUS other than "OK" in the response body.
+type APIError struct {
+	StatusCode int
+	URL        string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("teamwork: %s returned %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is reports whether target is the kind of error for e's status code.
+func (e *APIError) Is(target error) bool {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return target == errUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return target == errForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return target == errNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return target == errRateLimited
+	case e.StatusCode >= http.StatusInternalServerError:
+		return target == errServerError
+	}
+	return false
+}
+
+// errorBody holds the error message fields of v1 and v3 API responses.
+type errorBody struct {
+	Message string `json:"MESSAGE"`
+	Errors  []struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+// newAPIError creates the error for a response, reading the Teamwork error message
+// from its body when the body is JSON.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	err := &APIError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		err.URL = resp.Request.URL.String()
+	}
+
+	var parsed errorBody
+	if json.Unmarshal(body, &parsed) != nil {
+		return err
+	}
+	messages := []string{}
+	if parsed.Message != "" {
+		messages = append(messages, parsed.Message)
+	}
+	for _, e := range parsed.Errors {
+		if e.Detail != "" {
+			messages = append(messages, e.Detail)
+		} else if e.Title != "" {
+			messages = append(messages, e.Title)
+		}
+	}
+	err.Message = strings.Join(messages, "; ")
+	return err
+}
+
+// shouldIgnoreError treats resources that do not exist, such as the tasks of a
+// deleted project, as empty results.
+func shouldIgnoreError(_ context.Context, _ *plugin.QueryData, _ *plugin.HydrateData, err error) bool {
+	return errors.Is(err, errNotFound)
+}
+
+// shouldRetryError retries a hydrate call once more when the API was still throttling
+// or unavailable after fetchPage exhausted its own retries, for the same statuses
+// fetchPage retries. Setting max_retries to 0 turns this retry off as well.
+func shouldRetryError(_ context.Context, d *plugin.QueryData, _ *plugin.HydrateData, err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !shouldRetryStatus(apiErr.StatusCode) {
+		return false
+	}
+	if d != nil && maxRetries(GetConfig(d.Connection)) == 0 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package teamwork
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// defaultRequestsPerMinute is the request rate used until the API reports the
+// connection's limit in the x-ratelimit-limit header.
+const defaultRequestsPerMinute = 150
+
+// rateLimiter is a token bucket shared by every request on a connection, so that
+// tables queried in parallel stay within Teamwork's per-minute limit together. It
+// allows a burst of up to one second's worth of requests.
+type rateLimiter struct {
+	limiter *rate.Limiter
+
+	// fixed is set when the rate comes from requests_per_minute, in which case the
+	// x-ratelimit-limit header is ignored
+	fixed bool
+
+	mu        sync.Mutex
+	perMinute int
+}
+
+// newRateLimiter creates a limiter allowing perMinute requests a minute.
+func newRateLimiter(perMinute int, fixed bool) *rateLimiter {
+	return &rateLimiter{
+		limiter:   rate.NewLimiter(perMinuteLimit(perMinute), perMinuteBurst(perMinute)),
+		fixed:     fixed,
+		perMinute: perMinute,
+	}
+}
+
+// wait blocks until the limiter allows another request or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	return l.limiter.Wait(ctx)
+}
+
+// observe adjusts the limiter to the limit reported in a response's x-ratelimit-limit
+// header, unless the rate was fixed by requests_per_minute.
+func (l *rateLimiter) observe(resp *http.Response) {
+	if l.fixed {
+		return
+	}
+	perMinute, err := strconv.Atoi(resp.Header.Get("x-ratelimit-limit"))
+	if err != nil || perMinute <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if perMinute == l.perMinute {
+		return
+	}
+	l.perMinute = perMinute
+	l.limiter.SetLimit(perMinuteLimit(perMinute))
+	l.limiter.SetBurst(perMinuteBurst(perMinute))
+}
+
+// requestsPerMinute returns the limiter's current rate.
+func (l *rateLimiter) requestsPerMinute() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.perMinute
+}
+
+// perMinuteLimit converts a rate in requests a minute to a limiter rate.
+func perMinuteLimit(perMinute int) rate.Limit {
+	return rate.Limit(float64(perMinute) / 60)
+}
+
+// perMinuteBurst returns the burst size of a limiter allowing perMinute requests a minute.
+func perMinuteBurst(perMinute int) int {
+	if perMinute < 60 {
+		return 1
+	}
+	return perMinute / 60
+}
@@ -0,0 +1,72 @@
+package teamwork
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetryDelay caps the delay before any single retry, including delays requested
+// by the API through the Retry-After and x-ratelimit-reset headers.
+const maxRetryDelay = time.Minute
+
+// shouldRetryStatus reports whether a response with the given status code is
+// transient and the request worth retrying.
+func shouldRetryStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before retrying a request after resp. A delay
+// requested by the API is honoured, from the Retry-After header or, when throttled,
+// the x-ratelimit-reset header. Otherwise the delay grows exponentially from min with
+// each attempt, with jitter so that concurrent queries do not retry in lockstep.
+func retryDelay(resp *http.Response, attempt int, min time.Duration) time.Duration {
+	if delay, ok := headerRetryDelay(resp); ok {
+		if delay > maxRetryDelay {
+			return maxRetryDelay
+		}
+		return delay
+	}
+
+	delay := min
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Wait at least half the delay, plus a random share of the other half
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// headerRetryDelay reads the delay requested by the API from a response's headers.
+func headerRetryDelay(resp *http.Response) (time.Duration, bool) {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			if delay := time.Until(at); delay > 0 {
+				return delay, true
+			}
+			return 0, true
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("x-ratelimit-reset")); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,94 @@
+package teamwork
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// maxPageSize is the largest page size accepted by the Teamwork API.
+const maxPageSize = 500
+
+// limitPageSize sets the pageSize parameter of a list request from the query's LIMIT,
+// so that a query needing only a few rows is answered by a single small request.
+// params may be nil, and are returned unchanged for queries without a limit.
+func limitPageSize(d *plugin.QueryData, params url.Values) url.Values {
+	if params == nil {
+		params = url.Values{}
+	}
+	if d.QueryContext == nil {
+		return params
+	}
+	if limit := d.QueryContext.GetLimit(); limit > 0 {
+		if limit > maxPageSize {
+			limit = maxPageSize
+		}
+		params.Set("pageSize", strconv.FormatInt(limit, 10))
+	}
+	return params
+}
+
+// teamworkDateLayouts are the date formats returned by the Teamwork API, from
+// the compact YYYYMMDD form used by most v1 endpoints to full RFC 3339 timestamps.
+var teamworkDateLayouts = []string{
+	"20060102",
+	"2006-01-02",
+	time.RFC3339,
+}
+
+// parseTeamworkDate parses a date in any of the formats returned by the Teamwork API.
+// An empty string yields a nil time.
+func parseTeamworkDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range teamworkDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognised Teamwork date %q", value)
+}
+
+// transformTeamworkDate converts a Teamwork date string into a timestamp column value.
+// Columns holding Teamwork dates use it rather than exposing the raw string, so that
+// they can be compared with other timestamps in SQL. A date in an unrecognised format
+// is logged and left null rather than failing the whole query.
+func transformTeamworkDate(ctx context.Context, d *transform.TransformData) (interface{}, error) {
+	value, ok := d.Value.(string)
+	if !ok {
+		return nil, nil
+	}
+	t, err := parseTeamworkDate(value)
+	if err != nil {
+		plugin.Logger(ctx).Warn(fmt.Sprintf("transformTeamworkDate(): %s: %s", d.ColumnName, err.Error()))
+		return nil, nil
+	}
+	if t == nil {
+		return nil, nil
+	}
+	return *t, nil
+}
+
+// transformCommaSeparatedList splits a comma separated string of values, as used by
+// the Teamwork API for lists of IDs, into a slice.
+func transformCommaSeparatedList(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	value, ok := d.Value.(string)
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,39 @@
+package teamwork
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+func TestTransformTeamworkDate(t *testing.T) {
+	for _, test := range []struct {
+		Name    string
+		value   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"compact", "20240108", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), false},
+		{"ISO date", "2024-01-08", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), false},
+		{"RFC 3339", "2024-01-08T09:30:00Z", time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC), false},
+		{"empty", "", nil, false},
+		{"missing", nil, nil, false},
+		{"malformed", "08/01/2024", nil, false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			// Unrecognised dates are logged through the plugin logger
+			ctx := context.WithValue(context.Background(), context_key.Logger, hclog.NewNullLogger())
+			got, err := transformTeamworkDate(ctx, &transform.TransformData{Value: test.value})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("unexpected date: got %v, want %v", got, test.want)
+			}
+		})
+	}
+}